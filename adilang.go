@@ -0,0 +1,191 @@
+// Package adilang runs AdiLang programs from go, it puts the lexer, the parser and the
+// interpreter together behind a VM.
+//
+//	vm := adilang.NewVM(adilang.Options{Stdout: &out})
+//	vm.SetGlobal("name", "world")
+//	err := vm.Run(ctx, `out->"Hello ${name}"`)
+package adilang
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/AdityaByte/AdiLang/interpreter"
+	"github.com/AdityaByte/AdiLang/lexer"
+	"github.com/AdityaByte/AdiLang/parser"
+)
+
+// The values of the language, so a host program does not have to import the interpreter.
+type (
+	Value  = interpreter.Value
+	Int    = interpreter.Int
+	Float  = interpreter.Float
+	String = interpreter.String
+	Bool   = interpreter.Bool
+	Nil    = interpreter.Nil
+	List   = interpreter.List
+	Map    = interpreter.Map
+)
+
+// Options configure a VM, the zero value uses the standard input and output of the process.
+type Options struct {
+	// Stdout gets the out-> statements and Stderr the eprint calls, input reads Stdin.
+	Stdout io.Writer
+	Stderr io.Writer
+	Stdin  io.Reader
+
+	// MaxCallDepth is the maximum depth of nested function calls, 0 uses the default and
+	// it is at most interpreter.MaxCallDepthLimit.
+	MaxCallDepth int
+	// Seed makes math.random repeatable, 0 gives different numbers on every run.
+	Seed int64
+
+	// The limits for the programs which can not be trusted, 0 means no limit. MaxSteps counts
+	// the statements, loop iterations and calls of a run and Timeout is the time a run can take.
+	MaxSteps      int64
+	Timeout       time.Duration
+	MaxStringSize int // in bytes
+	MaxListSize   int // in elements
+	// MaxIntBits is the size of the biggest int, 0 uses interpreter.DefaultMaxIntBits.
+	MaxIntBits int
+	// Capabilities grant the fs, env and process modules, without them the programs can
+	// not reach outside of the VM.
+	Capabilities Capability
+}
+
+// Capability is a set of the capabilities a VM can grant to the programs.
+type Capability = interpreter.Capability
+
+const (
+	CapFilesystem = interpreter.CapFilesystem
+	CapEnv        = interpreter.CapEnv
+	CapProcess    = interpreter.CapProcess
+	CapNone       = interpreter.CapNone
+	CapAll        = interpreter.CapAll
+)
+
+// VM keeps the global variables between the runs, like the REPL does, so a program can
+// define functions and a later one can call them. A VM must not be used by multiple
+// goroutines at the same time.
+type VM struct {
+	interp  *interpreter.Interpreter
+	globals *interpreter.Environment
+	timeout time.Duration
+}
+
+func NewVM(opts Options) *VM {
+	interp := interpreter.NewInterpreter()
+	if opts.Stdout != nil {
+		interp.Stdout = opts.Stdout
+	}
+	if opts.Stderr != nil {
+		interp.Stderr = opts.Stderr
+	}
+	if opts.Stdin != nil {
+		interp.Stdin = opts.Stdin
+	}
+	if opts.MaxCallDepth > 0 {
+		interp.MaxCallDepth = opts.MaxCallDepth
+	}
+	if opts.Seed != 0 {
+		interp.Seed(opts.Seed)
+	}
+	interp.MaxSteps = opts.MaxSteps
+	interp.MaxStringSize = opts.MaxStringSize
+	interp.MaxListSize = opts.MaxListSize
+	if opts.MaxIntBits > 0 {
+		interp.MaxIntBits = opts.MaxIntBits
+	}
+	interp.Capabilities = opts.Capabilities
+
+	return &VM{
+		interp:  interp,
+		globals: interpreter.NewEnvironment(nil),
+		timeout: opts.Timeout,
+	}
+}
+
+// withTimeout gives the context of a run, it ends with a *TimeoutError once the time is up.
+func (vm *VM) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if vm.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeoutCause(ctx, vm.timeout, &TimeoutError{Timeout: vm.timeout})
+}
+
+// Run runs the program, it stops with an error when the context is cancelled.
+// The errors of the program are returned as an *Error.
+func (vm *VM) Run(ctx context.Context, src string) error {
+	return vm.run(ctx, "", src)
+}
+
+// RunFile reads and runs a .adi file, the positions in the errors have its name.
+func (vm *VM) RunFile(ctx context.Context, filename string) error {
+	code, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return vm.run(ctx, filename, string(code))
+}
+
+func (vm *VM) run(ctx context.Context, filename, src string) error {
+	p := parser.Parser{Tokens: lexer.LexFile(filename, src)}
+	nodes, err := p.Parse()
+	if err != nil {
+		return newError(SyntaxError, err, src)
+	}
+	ctx, cancel := vm.withTimeout(ctx)
+	defer cancel()
+	if err := vm.interp.InterpretContext(ctx, nodes, vm.globals); err != nil {
+		return newError(RuntimeError, err, src)
+	}
+	return nil
+}
+
+// Eval gives the value of an expression like "add(1, 2) * 3". When src is not a single
+// expression its statements are run and the value is nil.
+func (vm *VM) Eval(ctx context.Context, src string) (Value, error) {
+	ctx, cancel := vm.withTimeout(ctx)
+	defer cancel()
+	tokens := lexer.Lexer(src)
+
+	p := parser.Parser{Tokens: tokens}
+	if expr, err := p.ParseExpression(); err == nil {
+		value, err := vm.interp.EvaluateContext(ctx, expr, vm.globals)
+		if err != nil {
+			return nil, newError(RuntimeError, err, src)
+		}
+		return value, nil
+	}
+
+	p = parser.Parser{Tokens: tokens}
+	nodes, err := p.Parse()
+	if err != nil {
+		return nil, newError(SyntaxError, err, src)
+	}
+	if err := vm.interp.InterpretContext(ctx, nodes, vm.globals); err != nil {
+		return nil, newError(RuntimeError, err, src)
+	}
+	return Nil{}, nil
+}
+
+// SetGlobal declares a global variable, the value is a Value or a go value FromGo can convert.
+func (vm *VM) SetGlobal(name string, value interface{}) error {
+	v, err := FromGo(value)
+	if err != nil {
+		return err
+	}
+	vm.globals.Set(name, v)
+	return nil
+}
+
+// GetGlobal gives the value of a global variable, also the ones the programs declared.
+func (vm *VM) GetGlobal(name string) (Value, bool) {
+	value, err := vm.globals.Get(name)
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
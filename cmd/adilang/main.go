@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AdityaByte/AdiLang/diagnostics"
+	"github.com/AdityaByte/AdiLang/interpreter"
+	"github.com/AdityaByte/AdiLang/lexer"
+	"github.com/AdityaByte/AdiLang/parser"
+	"github.com/AdityaByte/AdiLang/repl"
+)
+
+// Exit codes of the adilang command.
+const (
+	exitSuccess      = 0
+	exitRuntimeError = 1 // the program failed while running
+	exitSyntaxError  = 2 // the program could not be parsed
+	exitUsage        = 3 // unknown command or wrong flags
+	exitReadError    = 4 // the file could not be read
+)
+
+const usage = `Usage:
+  adilang [flags] <file.adi> [-- args...]     run a file
+  adilang run [flags] <file.adi> [-- args...]
+  adilang eval [flags] -e <code> [-- args...] run the code given on the command line
+  adilang check <file.adi>                    only look for syntax errors
+  adilang tokens <file.adi>                   print the tokens of the file
+  adilang ast [--json] <file.adi>             print the syntax tree of the file
+  adilang repl [flags]                        start the interactive mode, also when no command is given
+
+A file named - is read from the standard input.
+The script arguments are in the list variable args.
+
+Flags:
+`
+
+// options are the flags of the commands, not every command uses all of them.
+type options struct {
+	errorFormat   string
+	maxCallDepth  int
+	seed          int64
+	seedSet       bool
+	maxSteps      int64
+	maxIntBits    int
+	maxStringSize int
+	maxListSize   int
+	timeout       time.Duration
+	allow         interpreter.Capability
+	json          bool
+	code          string
+}
+
+func newFlagSet(command string, opts *options) *flag.FlagSet {
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	fs.StringVar(&opts.errorFormat, "error-format", "human", "how errors are printed: human or json")
+	fs.IntVar(&opts.maxCallDepth, "max-call-depth", interpreter.DefaultMaxCallDepth, fmt.Sprintf("maximum depth of nested function calls, at most %d", interpreter.MaxCallDepthLimit))
+	fs.Func("seed", "seed of math.random so a run can be repeated, by default it changes on every run", func(value string) error {
+		seed, err := strconv.ParseInt(value, 10, 64)
+		opts.seed, opts.seedSet = seed, true
+		return err
+	})
+	fs.Int64Var(&opts.maxSteps, "max-steps", 0, "stop the program after this many steps, 0 for no limit")
+	fs.IntVar(&opts.maxIntBits, "max-int-bits", interpreter.DefaultMaxIntBits, "maximum size of an int in bits, 0 for no limit")
+	fs.IntVar(&opts.maxStringSize, "max-string-size", 0, "maximum size of a string in bytes, 0 for no limit")
+	fs.IntVar(&opts.maxListSize, "max-list-size", 0, "maximum number of elements of a list, 0 for no limit")
+	fs.DurationVar(&opts.timeout, "timeout", 0, "stop the program after this time, like 5s, 0 for no limit")
+	fs.Func("allow", "what the program can reach: a list of fs, env and process, or all (default none)", func(value string) error {
+		allow, err := interpreter.ParseCapabilities(value)
+		opts.allow = allow
+		return err
+	})
+	switch command {
+	case "ast":
+		fs.BoolVar(&opts.json, "json", false, "print the syntax tree as json")
+	case "eval":
+		fs.StringVar(&opts.code, "e", "", "the code to run")
+	}
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run executes the command line and gives the exit code.
+func run(args []string) int {
+	command := "repl"
+	if len(args) > 0 {
+		switch args[0] {
+		case "run", "eval", "check", "tokens", "ast", "repl":
+			command, args = args[0], args[1:]
+		case "help":
+			fmt.Fprint(os.Stdout, usage)
+			return exitSuccess
+		default:
+			// adilang file.adi works like adilang run file.adi.
+			command = "run"
+		}
+	}
+
+	opts := &options{}
+	fs := newFlagSet(command, opts)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitSuccess
+		}
+		return exitUsage
+	}
+	if opts.errorFormat != "human" && opts.errorFormat != "json" {
+		fmt.Fprintln(os.Stderr, "Unknown error format:", opts.errorFormat)
+		return exitUsage
+	}
+	if opts.maxCallDepth < 1 || opts.maxCallDepth > interpreter.MaxCallDepthLimit {
+		fmt.Fprintf(os.Stderr, "--max-call-depth must be between 1 and %d\n", interpreter.MaxCallDepthLimit)
+		return exitUsage
+	}
+
+	switch command {
+	case "repl":
+		if fs.NArg() > 0 {
+			return usageError("repl takes no arguments")
+		}
+		if err := repl.New(newInterpreter(opts), os.Stdin, os.Stdout).Run(); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			return exitReadError
+		}
+		return exitSuccess
+	case "eval":
+		if opts.code == "" {
+			return usageError("eval needs the code to run with -e")
+		}
+		return runSource("<eval>", opts.code, scriptArgs(fs.Args()), opts)
+	}
+
+	if fs.NArg() < 1 {
+		return usageError(command + " needs a file")
+	}
+	filename := fs.Arg(0)
+	if command != "run" && fs.NArg() > 1 {
+		return usageError(command + " takes a single file")
+	}
+	source, code := readSource(filename)
+	if code != exitSuccess {
+		return code
+	}
+	if filename == "-" {
+		filename = "<stdin>"
+	}
+
+	switch command {
+	case "tokens":
+		lexer.Fprint(os.Stdout, lexer.LexFile(filename, source))
+		return exitSuccess
+	case "check":
+		_, code := parse(filename, source, opts)
+		return code
+	case "ast":
+		nodes, code := parse(filename, source, opts)
+		if code != exitSuccess {
+			return code
+		}
+		return printAST(nodes, opts.json)
+	default:
+		return runSource(filename, source, scriptArgs(fs.Args()[1:]), opts)
+	}
+}
+
+func usageError(message string) int {
+	fmt.Fprintf(os.Stderr, "%s\n\n%s", message, usage)
+	return exitUsage
+}
+
+// scriptArgs gives the arguments which are passed to the script, the "--" in front of them is optional.
+func scriptArgs(args []string) []string {
+	if len(args) > 0 && args[0] == "--" {
+		return args[1:]
+	}
+	return args
+}
+
+// readSource reads the file, or the standard input when the filename is "-".
+func readSource(filename string) (string, int) {
+	if filename == "-" {
+		code, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error reading the standard input:", err)
+			return "", exitReadError
+		}
+		return string(code), exitSuccess
+	}
+
+	if !strings.HasSuffix(filename, ".adi") {
+		fmt.Fprintln(os.Stderr, "File extension must be .adi")
+		return "", exitUsage
+	}
+	code, err := os.ReadFile(filename)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error reading file:", err)
+		return "", exitReadError
+	}
+	return string(code), exitSuccess
+}
+
+func newInterpreter(opts *options) *interpreter.Interpreter {
+	interp := interpreter.NewInterpreter()
+	interp.MaxCallDepth = opts.maxCallDepth
+	interp.MaxSteps = opts.maxSteps
+	interp.MaxIntBits = opts.maxIntBits
+	interp.MaxStringSize = opts.maxStringSize
+	interp.MaxListSize = opts.maxListSize
+	interp.Capabilities = opts.allow
+	if opts.seedSet {
+		interp.Seed(opts.seed)
+	}
+	return interp
+}
+
+func parse(filename, source string, opts *options) ([]*parser.ASTNode, int) {
+	p := parser.Parser{Tokens: lexer.LexFile(filename, source)}
+	nodes, err := p.Parse()
+	if err != nil {
+		reportError(err, source, opts.errorFormat)
+		return nil, exitSyntaxError
+	}
+	return nodes, exitSuccess
+}
+
+// runSource parses and runs the program, the script arguments are given to it in the args variable.
+func runSource(filename, source string, args []string, opts *options) int {
+	nodes, code := parse(filename, source, opts)
+	if code != exitSuccess {
+		return code
+	}
+
+	env := interpreter.NewEnvironment(nil)
+	argList := &interpreter.List{Elements: make([]interpreter.Value, len(args))}
+	for i, arg := range args {
+		argList.Elements[i] = interpreter.String(arg)
+	}
+	env.Set("args", argList)
+
+	ctx := context.Background()
+	if opts.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, opts.timeout, &interpreter.TimeoutError{Timeout: opts.timeout})
+		defer cancel()
+	}
+	if err := newInterpreter(opts).InterpretContext(ctx, nodes, env); err != nil {
+		reportError(err, source, opts.errorFormat)
+		return exitRuntimeError
+	}
+	return exitSuccess
+}
+
+func printAST(nodes []*parser.ASTNode, asJSON bool) int {
+	if !asJSON {
+		parser.Fprint(os.Stdout, nodes)
+		return exitSuccess
+	}
+
+	data, err := json.MarshalIndent(nodes, "", "  ")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return exitRuntimeError
+	}
+	fmt.Println(string(data))
+	return exitSuccess
+}
+
+// reportError prints the error on stderr, diagnostics are rendered with the source snippet
+// or as json when the json error format is selected.
+func reportError(err error, source string, errorFormat string) {
+	var list diagnostics.List
+	var diag *diagnostics.Diagnostic
+
+	switch {
+	case errors.As(err, &list):
+	case errors.As(err, &diag):
+		list = diagnostics.List{diag}
+	default:
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return
+	}
+
+	for _, d := range list {
+		if errorFormat == "json" {
+			diagnostics.RenderJSON(os.Stderr, d)
+			continue
+		}
+		diagnostics.Render(os.Stderr, d, source)
+		fmt.Fprintln(os.Stderr)
+	}
+
+	if errorFormat != "json" && len(list) > 1 {
+		fmt.Fprintf(os.Stderr, "aborting due to %d previous errors\n", len(list))
+	}
+}
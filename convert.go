@@ -0,0 +1,293 @@
+package adilang
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+
+	"github.com/AdityaByte/AdiLang/interpreter"
+)
+
+var bigIntType = reflect.TypeOf((*big.Int)(nil))
+
+// FromGo converts a go value to a Value. The bools, numbers and strings become the ones of the
+// language, the slices and arrays lists and the maps with string, int or bool keys maps.
+// A Value is kept as it is and nil becomes nil.
+func FromGo(value interface{}) (Value, error) {
+	if value == nil {
+		return Nil{}, nil
+	}
+	if v, ok := value.(Value); ok {
+		return v, nil
+	}
+	return fromReflect(reflect.ValueOf(value))
+}
+
+func fromReflect(v reflect.Value) (Value, error) {
+	if v.Type() == bigIntType {
+		if v.IsNil() {
+			return Nil{}, nil
+		}
+		return interpreter.NewBigInt(v.Interface().(*big.Int)), nil
+	}
+	if v.CanInterface() {
+		if value, ok := v.Interface().(Value); ok {
+			return value, nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return Bool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Int(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return interpreter.NewBigInt(new(big.Int).SetUint64(v.Uint())), nil
+	case reflect.Float32, reflect.Float64:
+		return Float(v.Float()), nil
+	case reflect.String:
+		return String(v.String()), nil
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return Nil{}, nil
+		}
+		list := &List{Elements: make([]Value, v.Len())}
+		for i := range list.Elements {
+			element, err := fromReflect(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			list.Elements[i] = element
+		}
+		return list, nil
+	case reflect.Map:
+		if v.IsNil() {
+			return Nil{}, nil
+		}
+		// The keys are sorted so the order of the map does not change from run to run.
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+
+		m := interpreter.NewMap()
+		for _, k := range keys {
+			key, err := fromReflect(k)
+			if err != nil {
+				return nil, err
+			}
+			switch key.(type) {
+			case Int, String, Bool:
+			default:
+				return nil, fmt.Errorf("cannot use %s as a map key", k.Type())
+			}
+			value, err := fromReflect(v.MapIndex(k))
+			if err != nil {
+				return nil, err
+			}
+			m.Set(key, value)
+		}
+		return m, nil
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return Nil{}, nil
+		}
+		return fromReflect(v.Elem())
+	default:
+		return nil, fmt.Errorf("cannot convert %s to an adilang value", v.Type())
+	}
+}
+
+// ToGo converts a Value to a go value: int64 (or *big.Int when it does not fit), float64,
+// string, bool, nil, []interface{} for the lists and map[interface{}]interface{} for the maps.
+// The functions and modules are given as they are.
+func ToGo(value Value) interface{} {
+	return toGo(value, map[Value]interface{}{})
+}
+
+// toGo keeps the lists and maps already converted in done, so a list containing itself
+// becomes a slice containing itself instead of a conversion without an end.
+func toGo(value Value, done map[Value]interface{}) interface{} {
+	switch v := value.(type) {
+	case Int:
+		return int64(v)
+	case interpreter.BigInt:
+		return v.Big()
+	case Float:
+		return float64(v)
+	case String:
+		return string(v)
+	case Bool:
+		return bool(v)
+	case Nil, nil:
+		return nil
+	case *List:
+		if result, ok := done[v]; ok {
+			return result
+		}
+		result := make([]interface{}, len(v.Elements))
+		done[v] = result
+		for i, element := range v.Elements {
+			result[i] = toGo(element, done)
+		}
+		return result
+	case *Map:
+		if result, ok := done[v]; ok {
+			return result
+		}
+		result := make(map[interface{}]interface{}, v.Len())
+		done[v] = result
+		for _, key := range v.Keys() {
+			element, _ := v.Get(key)
+			result[toGo(key, done)] = toGo(element, done)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+var interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+// toReflect converts a Value to the go type t, the error tells what was expected.
+func toReflect(value Value, t reflect.Type) (reflect.Value, error) {
+	if reflect.TypeOf(value).AssignableTo(t) && t != interfaceType {
+		result := reflect.New(t).Elem()
+		result.Set(reflect.ValueOf(value))
+		return result, nil
+	}
+	if t == interfaceType {
+		result := reflect.New(t).Elem()
+		if v := ToGo(value); v != nil {
+			result.Set(reflect.ValueOf(v))
+		}
+		return result, nil
+	}
+	if t == bigIntType {
+		if n, ok := value.(Int); ok {
+			return reflect.ValueOf(big.NewInt(int64(n))), nil
+		}
+		if n, ok := value.(interpreter.BigInt); ok {
+			return reflect.ValueOf(n.Big()), nil
+		}
+		return reflect.Value{}, typeError(value, t)
+	}
+
+	result := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Bool:
+		b, ok := value.(Bool)
+		if !ok {
+			return reflect.Value{}, typeError(value, t)
+		}
+		result.SetBool(bool(b))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := value.(Int)
+		if large, isBig := value.(interpreter.BigInt); isBig {
+			return reflect.Value{}, fmt.Errorf("%s does not fit in %s", large, t)
+		}
+		if !ok {
+			return reflect.Value{}, typeError(value, t)
+		}
+		if result.OverflowInt(int64(n)) {
+			return reflect.Value{}, fmt.Errorf("%d does not fit in %s", n, t)
+		}
+		result.SetInt(int64(n))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var n *big.Int
+		switch v := value.(type) {
+		case Int:
+			n = big.NewInt(int64(v))
+		case interpreter.BigInt:
+			n = v.Big()
+		default:
+			return reflect.Value{}, typeError(value, t)
+		}
+		if !n.IsUint64() || result.OverflowUint(n.Uint64()) {
+			return reflect.Value{}, fmt.Errorf("%s does not fit in %s", n, t)
+		}
+		result.SetUint(n.Uint64())
+	case reflect.Float32, reflect.Float64:
+		// An int is accepted where a float is expected, like in the arithmetic.
+		switch v := value.(type) {
+		case Float:
+			result.SetFloat(float64(v))
+		case Int:
+			result.SetFloat(float64(v))
+		case interpreter.BigInt:
+			f, _ := new(big.Float).SetInt(v.Big()).Float64()
+			result.SetFloat(f)
+		default:
+			return reflect.Value{}, typeError(value, t)
+		}
+	case reflect.String:
+		s, ok := value.(String)
+		if !ok {
+			return reflect.Value{}, typeError(value, t)
+		}
+		result.SetString(string(s))
+	case reflect.Slice:
+		list, ok := value.(*List)
+		if !ok {
+			return reflect.Value{}, typeError(value, t)
+		}
+		result.Set(reflect.MakeSlice(t, len(list.Elements), len(list.Elements)))
+		for i, element := range list.Elements {
+			v, err := toReflect(element, t.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("element %d: %w", i, err)
+			}
+			result.Index(i).Set(v)
+		}
+	case reflect.Map:
+		m, ok := value.(*Map)
+		if !ok {
+			return reflect.Value{}, typeError(value, t)
+		}
+		result.Set(reflect.MakeMapWithSize(t, m.Len()))
+		for _, key := range m.Keys() {
+			k, err := toReflect(key, t.Key())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("key %s: %w", interpreter.Inspect(key), err)
+			}
+			element, _ := m.Get(key)
+			v, err := toReflect(element, t.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("value of %s: %w", interpreter.Inspect(key), err)
+			}
+			result.SetMapIndex(k, v)
+		}
+	default:
+		return reflect.Value{}, fmt.Errorf("cannot convert %s to %s", value.Type(), t)
+	}
+	return result, nil
+}
+
+func typeError(value Value, t reflect.Type) error {
+	return fmt.Errorf("expected %s, got %s", typeName(t), value.Type())
+}
+
+// typeName gives the name of the type of the language a go type is converted from.
+func typeName(t reflect.Type) string {
+	if t == bigIntType {
+		return "int"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.String:
+		return "string"
+	case reflect.Slice:
+		return "list"
+	case reflect.Map:
+		return "map"
+	default:
+		return t.String()
+	}
+}
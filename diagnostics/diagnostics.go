@@ -0,0 +1,86 @@
+package diagnostics
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AdityaByte/AdiLang/lexer"
+)
+
+type Severity string
+
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+)
+
+// Error codes, the E00xx codes are syntax errors and the E01xx codes are runtime errors.
+const (
+	CodeUnexpectedToken = "E0001"
+	CodeExpectedToken   = "E0002"
+	CodeInvalidNumber   = "E0003"
+	CodeUnexpectedEOF   = "E0004"
+	CodeInvalidString   = "E0005"
+	CodeInvalidComment  = "E0006"
+
+	CodeUndefinedVariable = "E0100"
+	CodeTypeMismatch      = "E0101"
+	CodeInvalidOperation  = "E0102"
+	CodeStackOverflow     = "E0103"
+	CodeIndexOutOfRange   = "E0104"
+	CodeKeyNotFound       = "E0105"
+	CodeCancelled         = "E0106"
+	CodeNativeError       = "E0107"
+	CodeStepLimit         = "E0108"
+	CodeSizeLimit         = "E0109"
+	CodePermissionDenied  = "E0110"
+)
+
+// Diagnostic is a structured error message pointing at a span of the source code.
+type Diagnostic struct {
+	Severity Severity       `json:"severity"`
+	Code     string         `json:"code"`
+	Message  string         `json:"message"`
+	Start    lexer.Position `json:"start"`
+	End      lexer.Position `json:"end"`
+	Help     string         `json:"help,omitempty"`
+	// Cause is the go error behind the diagnostic, like the one of a cancelled context.
+	Cause error `json:"-"`
+}
+
+// New creates an error diagnostic spanning from start to end.
+func New(code string, start, end lexer.Position, format string, args ...interface{}) *Diagnostic {
+	return &Diagnostic{
+		Severity: Error,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+		Start:    start,
+		End:      end,
+	}
+}
+
+// WithHelp attaches a hint which is shown below the snippet.
+func (d *Diagnostic) WithHelp(format string, args ...interface{}) *Diagnostic {
+	d.Help = fmt.Sprintf(format, args...)
+	return d
+}
+
+// Error keeps the short file:line:col form so a diagnostic can be used as a plain error.
+func (d *Diagnostic) Error() string {
+	return fmt.Sprintf("%s: %s", d.Start, d.Message)
+}
+
+func (d *Diagnostic) Unwrap() error {
+	return d.Cause
+}
+
+// List is a group of diagnostics reported together, e.g. all the syntax errors of a file.
+type List []*Diagnostic
+
+func (l List) Error() string {
+	messages := make([]string, len(l))
+	for i, d := range l {
+		messages[i] = d.Error()
+	}
+	return strings.Join(messages, "\n")
+}
@@ -0,0 +1,91 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Render writes the diagnostic in a human readable form, like
+//
+//	error[E0002]: Expected '=' keyword
+//	 --> hello.adi:1:7
+//	  |
+//	1 | var(x 5)
+//	  |       ^
+//	  = help: ...
+//
+// source is the code of the file the diagnostic points into, the snippet is
+// left out when the line can not be found in it.
+func Render(w io.Writer, d *Diagnostic, source string) {
+	fmt.Fprintf(w, "%s[%s]: %s\n", d.Severity, d.Code, d.Message)
+
+	lines := strings.Split(source, "\n")
+	if d.Start.Line < 1 || d.Start.Line > len(lines) {
+		fmt.Fprintf(w, " --> %s\n", d.Start)
+		if d.Help != "" {
+			fmt.Fprintf(w, " = help: %s\n", d.Help)
+		}
+		return
+	}
+
+	line := strings.TrimRight(lines[d.Start.Line-1], "\r")
+	number := strconv.Itoa(d.Start.Line)
+	gutter := strings.Repeat(" ", len(number))
+
+	fmt.Fprintf(w, "%s--> %s\n", gutter, d.Start)
+	fmt.Fprintf(w, "%s |\n", gutter)
+	fmt.Fprintf(w, "%s | %s\n", number, line)
+	fmt.Fprintf(w, "%s | %s\n", gutter, underline(line, d))
+	if d.Help != "" {
+		fmt.Fprintf(w, "%s = help: %s\n", gutter, d.Help)
+	}
+}
+
+// underline builds the caret line for the span of the diagnostic on the given source line.
+// Spans running over several lines are underlined up to the end of the first line.
+func underline(line string, d *Diagnostic) string {
+	chars := []rune(line)
+	from := d.Start.Column - 1
+	if from > len(chars) {
+		from = len(chars)
+	}
+	if from < 0 {
+		from = 0
+	}
+
+	to := len(chars)
+	if d.End.Line == d.Start.Line {
+		to = d.End.Column - 1
+	}
+	if to > len(chars) {
+		to = len(chars)
+	}
+
+	var b strings.Builder
+	// Keeping the tabs so the caret lines up with the code.
+	for _, char := range chars[:from] {
+		if char == '\t' {
+			b.WriteRune('\t')
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	b.WriteString("^")
+	if to-from > 1 {
+		b.WriteString(strings.Repeat("^", to-from-1))
+	}
+	return b.String()
+}
+
+// RenderJSON writes the diagnostic as a single line of JSON, which is easier for editors and CI to consume.
+func RenderJSON(w io.Writer, d *Diagnostic) error {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
@@ -0,0 +1,86 @@
+package adilang
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/AdityaByte/AdiLang/diagnostics"
+	"github.com/AdityaByte/AdiLang/interpreter"
+)
+
+// The errors of the limits, errors.As finds them in the *Error of a run which hit a limit.
+type (
+	StepLimitError  = interpreter.StepLimitError
+	TimeoutError    = interpreter.TimeoutError
+	CallDepthError  = interpreter.CallDepthError
+	StringSizeError = interpreter.StringSizeError
+	ListSizeError   = interpreter.ListSizeError
+	IntSizeError    = interpreter.IntSizeError
+	PermissionError = interpreter.PermissionError
+)
+
+// ErrorKind tells if a program could not be parsed or failed while running.
+type ErrorKind int
+
+const (
+	SyntaxError ErrorKind = iota + 1
+	RuntimeError
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case SyntaxError:
+		return "syntax error"
+	case RuntimeError:
+		return "runtime error"
+	default:
+		return fmt.Sprintf("ErrorKind(%d)", int(k))
+	}
+}
+
+// Error is the error of a program, the diagnostics have the codes, the messages and the
+// positions in the source.
+type Error struct {
+	Kind        ErrorKind
+	Diagnostics diagnostics.List
+	// Source is the code of the program, Render shows the lines of the errors from it.
+	Source string
+}
+
+// newError turns the diagnostics into an *Error, the other errors are returned as they are.
+func newError(kind ErrorKind, err error, source string) error {
+	var list diagnostics.List
+	var diag *diagnostics.Diagnostic
+
+	switch {
+	case errors.As(err, &list):
+	case errors.As(err, &diag):
+		list = diagnostics.List{diag}
+	default:
+		return err
+	}
+	return &Error{Kind: kind, Diagnostics: list, Source: source}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Kind, e.Diagnostics.Error())
+}
+
+// Unwrap gives the diagnostics, so errors.As finds a *diagnostics.Diagnostic and errors.Is
+// finds the cause of one, like context.DeadlineExceeded.
+func (e *Error) Unwrap() []error {
+	errs := make([]error, len(e.Diagnostics))
+	for i, d := range e.Diagnostics {
+		errs[i] = d
+	}
+	return errs
+}
+
+// Render prints the diagnostics with the lines of the source they point at, like the adilang command.
+func (e *Error) Render(w io.Writer) {
+	for _, d := range e.Diagnostics {
+		diagnostics.Render(w, d, e.Source)
+		fmt.Fprintln(w)
+	}
+}
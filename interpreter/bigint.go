@@ -0,0 +1,78 @@
+package interpreter
+
+import (
+	"math/big"
+
+	"github.com/AdityaByte/AdiLang/diagnostics"
+	"github.com/AdityaByte/AdiLang/parser"
+)
+
+// BigInt is an int which does not fit in 64 bits. The ints switch to it on their own
+// when a result overflows and back to Int when it fits again, so a BigInt never
+// holds a value an Int could hold and the language only sees one int type.
+type BigInt struct {
+	value *big.Int
+}
+
+func (BigInt) Type() string     { return "int" }
+func (b BigInt) String() string { return b.value.String() }
+
+// NewBigInt gives the int value of a big.Int, an Int when it fits in 64 bits.
+func NewBigInt(value *big.Int) Value {
+	return normalizeBig(new(big.Int).Set(value))
+}
+
+// Big gives a copy of the number.
+func (b BigInt) Big() *big.Int {
+	return new(big.Int).Set(b.value)
+}
+
+// normalizeBig gives an Int when the number fits in 64 bits and a BigInt otherwise.
+func normalizeBig(value *big.Int) Value {
+	if value.IsInt64() {
+		return Int(value.Int64())
+	}
+	return BigInt{value: value}
+}
+
+// toBig gives the value of an Int or BigInt as a big.Int, the result must not be modified.
+func toBig(value Value) (*big.Int, bool) {
+	switch v := value.(type) {
+	case Int:
+		return big.NewInt(int64(v)), true
+	case BigInt:
+		return v.value, true
+	}
+	return nil, false
+}
+
+func evaluateBigOperation(node *parser.ASTNode, operator string, left, right *big.Int) (Value, error) {
+	result := new(big.Int)
+	switch operator {
+	case "+":
+		result.Add(left, right)
+	case "-":
+		result.Sub(left, right)
+	case "*":
+		result.Mul(left, right)
+	case "/", "%":
+		if right.Sign() == 0 {
+			return nil, errorAt(node, diagnostics.CodeInvalidOperation, "division by zero")
+		}
+		// Quo and Rem truncate towards zero like the division of the small ints.
+		if operator == "/" {
+			result.Quo(left, right)
+		} else {
+			result.Rem(left, right)
+		}
+	default:
+		return nil, errorAt(node, diagnostics.CodeInvalidOperation, "Unsupported operator: %v", operator)
+	}
+	return normalizeBig(result), nil
+}
+
+// bigToFloat converts a big int to the nearest float.
+func bigToFloat(value *big.Int) Float {
+	f, _ := new(big.Float).SetInt(value).Float64()
+	return Float(f)
+}
@@ -0,0 +1,50 @@
+package interpreter
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestNormalizeBig(t *testing.T) {
+	tests := []struct {
+		value *big.Int
+		big   bool
+	}{
+		{big.NewInt(0), false},
+		{big.NewInt(math.MaxInt64), false},
+		{big.NewInt(math.MinInt64), false},
+		{new(big.Int).Add(big.NewInt(math.MaxInt64), big.NewInt(1)), true},
+		{new(big.Int).Sub(big.NewInt(math.MinInt64), big.NewInt(1)), true},
+	}
+	for _, tt := range tests {
+		got := normalizeBig(new(big.Int).Set(tt.value))
+		if _, isBig := got.(BigInt); isBig != tt.big || got.String() != tt.value.String() {
+			t.Errorf("normalizeBig(%s) = %s (%T), want big %v", tt.value, got, got, tt.big)
+		}
+	}
+}
+
+func TestBigOperationBackToInt(t *testing.T) {
+	above := new(big.Int).Add(big.NewInt(math.MaxInt64), big.NewInt(1))
+	tests := []struct {
+		operator string
+		right    *big.Int
+		want     Value
+	}{
+		{"-", big.NewInt(1), Int(math.MaxInt64)},
+		{"/", big.NewInt(2), Int(1 << 62)},
+		{"%", big.NewInt(10), Int(8)},
+		{"-", above, Int(0)},
+	}
+	for _, tt := range tests {
+		got, err := evaluateBigOperation(nil, tt.operator, above, tt.right)
+		if err != nil {
+			t.Errorf("%s %s %s: %v", above, tt.operator, tt.right, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s %s %s = %s (%T), want %s", above, tt.operator, tt.right, got, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package interpreter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AdityaByte/AdiLang/diagnostics"
+	"github.com/AdityaByte/AdiLang/parser"
+)
+
+// Builtin is a function provided by the interpreter itself, node is the call
+// so the errors can point at it.
+type Builtin struct {
+	Name  string
+	Arity int // -1 when the number of arguments is checked by the function
+	// Requires are the capabilities the interpreter must have been granted to call it.
+	Requires Capability
+	Fn       func(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error)
+}
+
+func (b *Builtin) Type() string { return "function" }
+
+func (b *Builtin) String() string {
+	return fmt.Sprintf("<builtin %s>", b.Name)
+}
+
+// builtins is looked up when a name is not found in the environment,
+// so a variable with the same name hides the builtin.
+var builtins = map[string]*Builtin{}
+
+func registerBuiltin(name string, arity int, fn func(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error)) {
+	builtins[name] = &Builtin{Name: name, Arity: arity, Fn: fn}
+}
+
+func init() {
+	registerBuiltin("len", 1, builtinLen)
+	registerBuiltin("append", -1, builtinAppend)
+	registerBuiltin("pop", -1, builtinPop)
+	registerBuiltin("has", 2, builtinHas)
+	registerBuiltin("delete", 2, builtinDelete)
+	registerBuiltin("keys", 1, builtinKeys)
+	registerBuiltin("input", -1, builtinInput)
+	registerBuiltin("eprint", -1, builtinEprint)
+}
+
+// argError creates an error pointing at the i-th argument of the call.
+func argError(node *parser.ASTNode, i int, format string, args ...interface{}) *diagnostics.Diagnostic {
+	target := node
+	if i+1 < len(node.Children) {
+		target = node.Children[i+1]
+	}
+	return errorAt(target, diagnostics.CodeTypeMismatch, format, args...)
+}
+
+// tooLargeArg reports a big int given to a builtin which needs an int of 64 bits.
+func tooLargeArg(node *parser.ASTNode, i int, name string, value Value) *diagnostics.Diagnostic {
+	d := argError(node, i, "%s argument is too large: %s", name, value)
+	d.Code = diagnostics.CodeInvalidOperation
+	return d
+}
+
+func listArg(node *parser.ASTNode, name string, args []Value, i int) (*List, error) {
+	list, ok := args[i].(*List)
+	if !ok {
+		return nil, argError(node, i, "%s expects a list, got %s", name, args[i].Type())
+	}
+	return list, nil
+}
+
+func mapArg(node *parser.ASTNode, name string, args []Value, i int) (*Map, error) {
+	m, ok := args[i].(*Map)
+	if !ok {
+		return nil, argError(node, i, "%s expects a map, got %s", name, args[i].Type())
+	}
+	return m, nil
+}
+
+// len(xs) gives the number of elements of a list or map, or the characters of a string.
+func builtinLen(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	switch v := args[0].(type) {
+	case *List:
+		return Int(len(v.Elements)), nil
+	case *Map:
+		return Int(v.Len()), nil
+	case String:
+		return Int(utf8.RuneCountInString(string(v))), nil
+	default:
+		return nil, argError(node, 0, "len expects a list, map or string, got %s", args[0].Type())
+	}
+}
+
+// append(xs, values...) adds the values at the end of the list and returns the list.
+func builtinAppend(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	if len(args) < 2 {
+		return nil, errorAt(node, diagnostics.CodeInvalidOperation, "append expects a list and at least one value")
+	}
+	list, err := listArg(node, "append", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	list.Elements = append(list.Elements, args[1:]...)
+	return list, nil
+}
+
+// pop(xs) removes and returns the last element, pop(xs, i) the element at index i.
+func builtinPop(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, errorAt(node, diagnostics.CodeInvalidOperation, "pop expects a list and an optional index")
+	}
+	list, err := listArg(node, "pop", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Elements) == 0 {
+		return nil, errorAt(node, diagnostics.CodeIndexOutOfRange, "pop from an empty list")
+	}
+
+	index := len(list.Elements) - 1
+	if len(args) == 2 {
+		index, err = listIndex(node.Children[2], args[1], len(list.Elements))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	value := list.Elements[index]
+	list.Elements = append(list.Elements[:index], list.Elements[index+1:]...)
+	return value, nil
+}
+
+// has(m, key) tells if the key is in the map.
+func builtinHas(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	m, err := mapArg(node, "has", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkKey(node.Children[2], args[1]); err != nil {
+		return nil, err
+	}
+	_, ok := m.Get(args[1])
+	return Bool(ok), nil
+}
+
+// delete(m, key) removes the key from the map and reports if it was there.
+func builtinDelete(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	m, err := mapArg(node, "delete", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkKey(node.Children[2], args[1]); err != nil {
+		return nil, err
+	}
+	return Bool(m.Delete(args[1])), nil
+}
+
+// keys(m) gives a list of the keys in insertion order.
+func builtinKeys(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	m, err := mapArg(node, "keys", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &List{Elements: m.Keys()}, nil
+}
+
+// input() reads a line from the standard input without the newline, input(prompt) shows
+// the prompt first. At the end of the input it gives nil.
+func builtinInput(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	if len(args) > 1 {
+		return nil, errorAt(node, diagnostics.CodeInvalidOperation, "input expects an optional prompt")
+	}
+	if len(args) == 1 {
+		fmt.Fprint(interp.Stdout, args[0].String())
+	}
+
+	if interp.stdin == nil {
+		// NewReader keeps a Stdin which already is a *bufio.Reader, like the one of the REPL.
+		interp.stdin = bufio.NewReader(interp.Stdin)
+	}
+	line, err := interp.stdin.ReadString('\n')
+	if err != nil && line == "" {
+		if err == io.EOF {
+			return Nil{}, nil
+		}
+		return nil, errorAt(node, diagnostics.CodeInvalidOperation, "cannot read the input: %v", err)
+	}
+	return String(strings.TrimRight(line, "\r\n")), nil
+}
+
+// eprint(values...) prints the values separated by spaces on the standard error.
+func builtinEprint(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = arg.String()
+	}
+	fmt.Fprintln(interp.Stderr, strings.Join(parts, " "))
+	return Nil{}, nil
+}
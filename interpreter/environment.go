@@ -1,37 +1,65 @@
 package interpreter
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+)
 
 type Environment struct {
-	variables map[string]interface{}
-	parent *Environment
+	variables map[string]Value
+	parent    *Environment
 }
 
 func NewEnvironment(parent *Environment) *Environment {
 	return &Environment{
-		variables: make(map[string]interface{}),
-		parent: parent,
+		variables: make(map[string]Value),
+		parent:    parent,
 	}
 }
 
-func (e *Environment) Set(name string, value interface{}) {
+func (e *Environment) Set(name string, value Value) {
 	e.variables[name] = value
 }
 
-func (e *Environment) Get(name string) (interface{}, error) {
+func (e *Environment) Get(name string) (Value, error) {
 	// The exists is the lookup function which was managed by the map of the environment so
 	// in this when the variable exists in the current scope it return the true value
 	value, exists := e.variables[name]
 
-	// Here we have added the thing that if the variable does not exists in the current scope then it will 
+	// Here we have added the thing that if the variable does not exists in the current scope then it will
 	// check for the parent scope if the parent scope is not nil and the variable exists in that scope then it will return that variable value
 	if !exists && e.parent != nil {
 		return e.parent.Get(name)
 	}
 
 	if !exists {
-		return nil, fmt.Errorf("undefined variable: %s",name)
+		return nil, fmt.Errorf("undefined variable: %s", name)
 	}
 
 	return value, nil
-}
\ No newline at end of file
+}
+
+// Assign updates an existing variable in the scope where it was declared,
+// unlike Set it never creates a new variable.
+func (e *Environment) Assign(name string, value Value) error {
+	if _, exists := e.variables[name]; exists {
+		e.variables[name] = value
+		return nil
+	}
+
+	if e.parent != nil {
+		return e.parent.Assign(name, value)
+	}
+
+	return fmt.Errorf("cannot assign to undeclared variable: %s", name)
+}
+
+// Names gives the sorted names of the variables declared in this scope, without the parent scopes.
+func (e *Environment) Names() []string {
+	names := make([]string, 0, len(e.variables))
+	for name := range e.variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,171 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AdityaByte/AdiLang/diagnostics"
+	"github.com/AdityaByte/AdiLang/parser"
+)
+
+// Function is a user defined function, Env is the environment where it was
+// declared so the body can see the variables around the declaration.
+type Function struct {
+	Name   string
+	Params []string
+	Body   *parser.ASTNode
+	Env    *Environment
+}
+
+func (f *Function) Type() string { return "function" }
+
+// String shows the function as <fundude name>, anonymous functions have no name.
+func (f *Function) String() string {
+	if f.Name == "" {
+		return "<fundude>"
+	}
+	return fmt.Sprintf("<fundude %s>", f.Name)
+}
+
+// displayName is how the function is called in the error messages.
+func (f *Function) displayName() string {
+	if f.Name == "" {
+		return "anonymous function"
+	}
+	return f.Name
+}
+
+// returnSignal is passed up like an error by the statements of a function body
+// until the call which is running the function catches it.
+type returnSignal struct {
+	value Value
+}
+
+func (r *returnSignal) Error() string {
+	return "return outside of a function"
+}
+
+// newFunction creates the function value for a declaration or a function literal,
+// capturing the environment it is created in.
+func newFunction(node *parser.ASTNode, env *Environment) *Function {
+	params := node.Children[0].Children
+	fn := &Function{
+		Params: make([]string, len(params)),
+		Body:   node.Children[1],
+		Env:    env,
+	}
+	if name, ok := node.Value.(string); ok {
+		fn.Name = name
+	}
+	for i, param := range params {
+		fn.Params[i] = param.Value.(string)
+	}
+	return fn
+}
+
+func (interp *Interpreter) executeFunctionDeclaration(node *parser.ASTNode, env *Environment) {
+	fn := newFunction(node, env)
+	env.Set(fn.Name, fn)
+}
+
+func (interp *Interpreter) executeReturn(node *parser.ASTNode, env *Environment) error {
+	if len(node.Children) == 0 {
+		return &returnSignal{value: Nil{}}
+	}
+
+	value, err := interp.evaluateExpression(node.Children[0], env)
+	if err != nil {
+		return err
+	}
+	return &returnSignal{value: value}
+}
+
+func (interp *Interpreter) evaluateCall(node *parser.ASTNode, env *Environment) (Value, error) {
+	callee, err := interp.evaluateExpression(node.Children[0], env)
+	if err != nil {
+		return nil, err
+	}
+
+	argNodes := node.Children[1:]
+	args := make([]Value, len(argNodes))
+	for i, argNode := range argNodes {
+		args[i], err = interp.evaluateExpression(argNode, env)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return interp.callValue(node, callee, args)
+}
+
+// callValue calls a function or builtin value with already evaluated arguments,
+// node is the call expression used for the error messages.
+func (interp *Interpreter) callValue(node *parser.ASTNode, callee Value, args []Value) (Value, error) {
+	if err := interp.tick(node); err != nil {
+		return nil, err
+	}
+
+	switch fn := callee.(type) {
+	case *Function:
+		if len(args) != len(fn.Params) {
+			return nil, errorAt(node, diagnostics.CodeInvalidOperation, "%s expects %d arguments, got %d", fn.displayName(), len(fn.Params), len(args))
+		}
+		return interp.callFunction(node, fn, args)
+	case *Builtin:
+		if fn.Arity >= 0 && len(args) != fn.Arity {
+			return nil, errorAt(node, diagnostics.CodeInvalidOperation, "%s expects %d arguments, got %d", fn.Name, fn.Arity, len(args))
+		}
+		if fn.Requires != 0 && interp.Capabilities&fn.Requires != fn.Requires {
+			return nil, limitError(node, diagnostics.CodePermissionDenied, &PermissionError{Name: fn.Name, Capability: fn.Requires})
+		}
+		value, err := fn.Fn(interp, node, args)
+		if err != nil {
+			return nil, err
+		}
+		if err := interp.checkSize(node, value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	default:
+		return nil, errorAt(node.Children[0], diagnostics.CodeTypeMismatch, "cannot call a value of type %s", callee.Type())
+	}
+}
+
+// maxCallDepth gives MaxCallDepth kept between 1 and MaxCallDepthLimit, 0 uses the default.
+func (interp *Interpreter) maxCallDepth() int {
+	switch {
+	case interp.MaxCallDepth <= 0:
+		return DefaultMaxCallDepth
+	case interp.MaxCallDepth > MaxCallDepthLimit:
+		return MaxCallDepthLimit
+	}
+	return interp.MaxCallDepth
+}
+
+// callFunction runs the body of the function in a new scope on top of the
+// environment where the function was declared.
+func (interp *Interpreter) callFunction(node *parser.ASTNode, fn *Function, args []Value) (Value, error) {
+	if limit := interp.maxCallDepth(); interp.depth >= limit {
+		d := errorAt(node, diagnostics.CodeStackOverflow, "stack overflow: more than %d nested calls", limit)
+		d.Cause = &CallDepthError{Limit: limit}
+		return nil, d
+	}
+	interp.depth++
+	defer func() { interp.depth-- }()
+
+	callEnv := NewEnvironment(fn.Env)
+	for i, param := range fn.Params {
+		callEnv.Set(param, args[i])
+	}
+
+	err := interp.executeStatement(fn.Body.Children, callEnv)
+
+	var ret *returnSignal
+	if errors.As(err, &ret) {
+		return ret.value, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Nil{}, nil
+}
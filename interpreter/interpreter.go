@@ -1,49 +1,142 @@
 package interpreter
 
 import (
+	"bufio"
+	"context"
 	"fmt"
-
+	"io"
+	"math"
+	"math/big"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AdityaByte/AdiLang/diagnostics"
 	"github.com/AdityaByte/AdiLang/parser"
 )
 
-func executeStatement(nodes []*parser.ASTNode, env *Environment) error {
+// DefaultMaxCallDepth is how deep the function calls can be nested before
+// the program is stopped with a stack overflow error.
+const DefaultMaxCallDepth = 1000
+
+// MaxCallDepthLimit is the highest MaxCallDepth, deeper calls would overflow the stack
+// of Go which crashes the process instead of stopping the program.
+const MaxCallDepthLimit = 100_000
+
+// DefaultMaxIntBits is the size of the biggest int, about 315 thousand digits. Unlike the
+// other limits it is on by default, math.pow(2, 10000000000) would otherwise run for hours.
+const DefaultMaxIntBits = 1 << 20
+
+// Interpreter walks the AST and executes it, it keeps the state which
+// is shared by the whole run like the current call depth.
+type Interpreter struct {
+	// MaxCallDepth is kept at most MaxCallDepthLimit, 0 uses DefaultMaxCallDepth.
+	MaxCallDepth int
+	// MaxSteps, MaxStringSize (in bytes) and MaxListSize limit the programs, 0 means no limit.
+	MaxSteps      int64
+	MaxStringSize int
+	MaxListSize   int
+	// MaxIntBits limits the size of the big ints, 0 means no limit.
+	MaxIntBits int
+	// Capabilities are the ones granted to the builtins, by default they have none.
+	Capabilities Capability
+
+	// out-> writes to Stdout, eprint to Stderr and input reads from Stdin.
+	Stdout io.Writer
+	Stderr io.Writer
+	Stdin  io.Reader
+
+	depth  int
+	steps  int64
+	random *rand.Rand
+	stdin  *bufio.Reader
+	// ctx is the context of the running program, it is checked in the loops and calls.
+	ctx context.Context
+}
+
+func NewInterpreter() *Interpreter {
+	return &Interpreter{
+		MaxCallDepth: DefaultMaxCallDepth,
+		MaxIntBits:   DefaultMaxIntBits,
+		Stdout:       os.Stdout,
+		Stderr:       os.Stderr,
+		Stdin:        os.Stdin,
+		random:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Seed makes math.random give the same numbers on every run with the same seed.
+func (interp *Interpreter) Seed(seed int64) {
+	interp.random.Seed(seed)
+}
+
+// errorAt creates a diagnostic pointing at the given node.
+func errorAt(node *parser.ASTNode, code string, format string, args ...interface{}) *diagnostics.Diagnostic {
+	return diagnostics.New(code, node.Start, node.End, format, args...)
+}
+
+func (interp *Interpreter) executeStatement(nodes []*parser.ASTNode, env *Environment) error {
 	for _, node := range nodes {
+		if err := interp.tick(node); err != nil {
+			return err
+		}
 		// fmt.Println("node type:", node.Type)
 		switch node.Type {
 		case parser.NodeVariableDeclaration:
-			if err := executeVariableDeclaration(node, env); err != nil {
+			if err := interp.executeVariableDeclaration(node, env); err != nil {
 				return err
 			}
 		case parser.NodePrint:
-			if err := executePrintStatement(node, env); err != nil {
+			if err := interp.executePrintStatement(node, env); err != nil {
 				return err
 			}
 		case parser.NodeForLoop:
-			if err := executeForLoop(node, env); err != nil {
+			if err := interp.executeForLoop(node, env); err != nil {
+				return err
+			}
+		case parser.NodeWhileLoop:
+			if err := interp.executeWhileLoop(node, env); err != nil {
 				return err
 			}
+		case parser.NodeBreak:
+			return errBreak
+		case parser.NodeContinue:
+			return errContinue
 		case parser.NodeIfStatement:
-			if err := executeIfStatement(node, env); err != nil {
+			if err := interp.executeIfStatement(node, env); err != nil {
 				return err
 			}
 		case parser.NodeBlock:
-			if err := executeBlock(node, env); err != nil {
+			if err := interp.executeBlock(node, env); err != nil {
+				return err
+			}
+		case parser.NodeFunctionDeclaration:
+			interp.executeFunctionDeclaration(node, env)
+		case parser.NodeReturn:
+			return interp.executeReturn(node, env)
+		case parser.NodeAssignment:
+			if err := interp.executeAssignment(node, env); err != nil {
+				return err
+			}
+		case parser.NodeExpressionStatement:
+			if _, err := interp.evaluateExpression(node.Children[0], env); err != nil {
 				return err
 			}
 		default:
-			return fmt.Errorf("Unknown statement : %v", node.Type)
+			return errorAt(node, diagnostics.CodeInvalidOperation, "Unknown statement : %v", node.Type)
 		}
 	}
 
 	return nil
 }
 
-func executeBlock(node *parser.ASTNode, parentEnv *Environment) error {
+func (interp *Interpreter) executeBlock(node *parser.ASTNode, parentEnv *Environment) error {
 
 	blockEnv := NewEnvironment(parentEnv)
 
 	for _, stmt := range node.Children {
-		if err := executeStatement([]*parser.ASTNode{stmt}, blockEnv); err != nil {
+		if err := interp.executeStatement([]*parser.ASTNode{stmt}, blockEnv); err != nil {
 			return err
 		}
 	}
@@ -51,14 +144,14 @@ func executeBlock(node *parser.ASTNode, parentEnv *Environment) error {
 	return nil
 }
 
-func executeVariableDeclaration(node *parser.ASTNode, env *Environment) error {
+func (interp *Interpreter) executeVariableDeclaration(node *parser.ASTNode, env *Environment) error {
 	if node.Type != parser.NodeVariableDeclaration {
-		return fmt.Errorf("expected variable declaration")
+		return errorAt(node, diagnostics.CodeInvalidOperation, "expected variable declaration")
 	}
 
 	name := node.Value.(string)
 
-	value, err := evaluateExpression(node.Children[0], env)
+	value, err := interp.evaluateExpression(node.Children[0], env)
 
 	if err != nil {
 		return err
@@ -68,42 +161,146 @@ func executeVariableDeclaration(node *parser.ASTNode, env *Environment) error {
 	return nil
 }
 
-func executePrintStatement(node *parser.ASTNode, env *Environment) error {
-	value, err := evaluateExpression(node.Value.(*parser.ASTNode), env)
+// executeAssignment updates an existing variable, for the compound operators like += the
+// new value is computed from the current one.
+func (interp *Interpreter) executeAssignment(node *parser.ASTNode, env *Environment) error {
+	target := node.Children[0]
+	operator := node.Value.(string)
+
+	value, err := interp.evaluateExpression(node.Children[1], env)
 	if err != nil {
 		return err
 	}
 
-	if node.Children != nil {
-		anotherValue, err := evaluateExpression(node.Children[0], env)
+	if target.Type == parser.NodeIndex {
+		return interp.assignIndex(node, env, operator, value)
+	}
+	name := target.Value.(string)
+
+	if operator != "=" {
+		current, err := env.Get(name)
 		if err != nil {
+			return errorAt(target, diagnostics.CodeUndefinedVariable, "%v", err)
+		}
+		value, err = evaluateBinaryOperation(node, strings.TrimSuffix(operator, "="), current, value)
+		if err != nil {
+			return err
+		}
+		if err := interp.checkSize(node, value); err != nil {
 			return err
 		}
+	}
 
-		fmt.Println(value.(string) + anotherValue.(string))
-		return nil
+	if err := env.Assign(name, value); err != nil {
+		return errorAt(target, diagnostics.CodeUndefinedVariable, "%v", err).WithHelp("declare it first with var(%s = ...)", name)
+	}
+	return nil
+}
+
+func (interp *Interpreter) executePrintStatement(node *parser.ASTNode, env *Environment) error {
+	value, err := interp.evaluateExpression(node.Value.(*parser.ASTNode), env)
+	if err != nil {
+		return err
 	}
 
-	fmt.Println(value)
+	fmt.Fprintln(interp.Stdout, value.String())
 	return nil
 }
 
-func evaluateExpression(node *parser.ASTNode, env *Environment) (interface{}, error) {
+func (interp *Interpreter) evaluateExpression(node *parser.ASTNode, env *Environment) (Value, error) {
 	switch node.Type {
 	case parser.NodeStringLiteral:
-		return node.Value, nil
+		return String(node.Value.(string)), nil
 	case parser.NodeNumberLiteral:
-		return node.Value, nil
+		switch value := node.Value.(type) {
+		case float64:
+			return Float(value), nil
+		case *big.Int:
+			return normalizeBig(value), nil
+		}
+		return Int(node.Value.(int64)), nil
 	case parser.NodeIdentifier:
-		return env.Get(node.Value.(string))
+		value, err := env.Get(node.Value.(string))
+		if err != nil {
+			if builtin, ok := builtins[node.Value.(string)]; ok {
+				return builtin, nil
+			}
+			if module, ok := modules[node.Value.(string)]; ok {
+				return module, nil
+			}
+			return nil, errorAt(node, diagnostics.CodeUndefinedVariable, "%v", err)
+		}
+		return value, nil
+	case parser.NodeBooleanLiteral:
+		return Bool(node.Value.(bool)), nil
+	case parser.NodeBinaryOperation:
+		operator := node.Value.(string)
+		if operator == "&&" || operator == "||" {
+			return interp.evaluateLogicalOperation(node, env)
+		}
+
+		left, err := interp.evaluateExpression(node.Children[0], env)
+		if err != nil {
+			return nil, err
+		}
+		right, err := interp.evaluateExpression(node.Children[1], env)
+		if err != nil {
+			return nil, err
+		}
+		value, err := evaluateBinaryOperation(node, operator, left, right)
+		if err != nil {
+			return nil, err
+		}
+		if err := interp.checkSize(node, value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	case parser.NodeUnaryOperation:
+		operand, err := interp.evaluateExpression(node.Children[0], env)
+		if err != nil {
+			return nil, err
+		}
+		return evaluateUnaryOperation(node, operand)
+	case parser.NodeCall:
+		return interp.evaluateCall(node, env)
+	case parser.NodeFunctionLiteral:
+		return newFunction(node, env), nil
+	case parser.NodeListLiteral:
+		return interp.evaluateListLiteral(node, env)
+	case parser.NodeMapLiteral:
+		return interp.evaluateMapLiteral(node, env)
+	case parser.NodeIndex:
+		return interp.evaluateIndex(node, env)
+	case parser.NodeSlice:
+		return interp.evaluateSlice(node, env)
+	case parser.NodeMember:
+		return interp.evaluateMember(node, env)
+	case parser.NodeInterpolation:
+		return interp.evaluateInterpolation(node, env)
 	default:
-		return nil, fmt.Errorf("unsupported expression type: %s", node.Type)
+		return nil, errorAt(node, diagnostics.CodeInvalidOperation, "unsupported expression type: %s", node.Type)
 	}
 }
 
-func executeForLoop(node *parser.ASTNode, env *Environment) error {
+// evaluateInterpolation joins the text parts with the values of the expressions,
+// they are shown the same way out-> prints them.
+func (interp *Interpreter) evaluateInterpolation(node *parser.ASTNode, env *Environment) (Value, error) {
+	var result strings.Builder
+	for _, part := range node.Children {
+		value, err := interp.evaluateExpression(part, env)
+		if err != nil {
+			return nil, err
+		}
+		result.WriteString(value.String())
+		if err := interp.checkStringSize(node, result.Len()); err != nil {
+			return nil, err
+		}
+	}
+	return String(result.String()), nil
+}
+
+func (interp *Interpreter) executeForLoop(node *parser.ASTNode, env *Environment) error {
 
-	loopVar := node.Value.(string)
 	rangeNode := node.Children[0]
 	body := node.Children[1] // NodeBlock
 
@@ -111,79 +308,246 @@ func executeForLoop(node *parser.ASTNode, env *Environment) error {
 	// fmt.Printf("body: %T and its type %T", body, body.Children)
 
 	if rangeNode.Type != parser.NodeRange {
-		return fmt.Errorf("expected range")
+		return interp.executeForEach(node, env)
 	}
+	loopVar := node.Value.(string)
 
-	limit := rangeNode.Value.(int)
-
-	loopEnv := NewEnvironment(env)
+	start, end, step, err := interp.evaluateRange(rangeNode, env)
+	if err != nil {
+		return err
+	}
 
-	for i := 0; i < limit; i++ {
-		loopEnv.Set(loopVar, i)
+	for i := start; (step > 0 && i < end) || (step < 0 && i > end); i += step {
+		if err := interp.tick(node); err != nil {
+			return err
+		}
+		// Every iteration has its own variable, so a closure made in the body keeps its i.
+		loopEnv := NewEnvironment(env)
+		loopEnv.Set(loopVar, Int(i))
 		// fmt.Println("loopvar value: ", loopEnv)
-		if err := executeBlock(body, loopEnv); err != nil {
+		if stop, err := loopControl(interp.executeBlock(body, loopEnv)); stop {
 			return err
 		}
+		// Near the ends of the ints the next i would wrap around instead of passing the end.
+		if (step > 0 && i > math.MaxInt-step) || (step < 0 && i < math.MinInt-step) {
+			break
+		}
 	}
 	return nil
 }
 
-func executeIfStatement(node *parser.ASTNode, env *Environment) error {
+// executeForEach runs the body for every element of a list or every key of a map.
+// With two loop variables they get the index and element of a list, or the key and value of a map.
+// The elements are taken when the loop starts so changing the list or map inside the body does not affect the loop.
+func (interp *Interpreter) executeForEach(node *parser.ASTNode, env *Environment) error {
+	iterableNode := node.Children[0]
+	body := node.Children[1]
 
-	if len(node.Children) < 2 {
-		return fmt.Errorf("invalid if statement missing conditon and body")
+	iterable, err := interp.evaluateExpression(iterableNode, env)
+	if err != nil {
+		return err
 	}
 
+	var firsts, seconds []Value
+	switch it := iterable.(type) {
+	case *List:
+		seconds = make([]Value, len(it.Elements))
+		copy(seconds, it.Elements)
+		firsts = make([]Value, len(seconds))
+		for i := range seconds {
+			firsts[i] = Int(i)
+		}
+	case *Map:
+		firsts = it.Keys()
+		seconds = make([]Value, len(firsts))
+		for i, key := range firsts {
+			seconds[i], _ = it.Get(key)
+		}
+	default:
+		return errorAt(iterableNode, diagnostics.CodeTypeMismatch, "cannot iterate over a value of type %s", iterable.Type())
+	}
+
+	for i := range firsts {
+		if err := interp.tick(node); err != nil {
+			return err
+		}
+		loopEnv := NewEnvironment(env)
+		switch vars := node.Value.(type) {
+		case string:
+			// A single variable gets the element of a list or the key of a map.
+			if _, isList := iterable.(*List); isList {
+				loopEnv.Set(vars, seconds[i])
+			} else {
+				loopEnv.Set(vars, firsts[i])
+			}
+		case []string:
+			loopEnv.Set(vars[0], firsts[i])
+			loopEnv.Set(vars[1], seconds[i])
+		}
+
+		if stop, err := loopControl(interp.executeBlock(body, loopEnv)); stop {
+			return err
+		}
+	}
+	return nil
+}
+
+// loopSignal is returned like an error by break and continue, the enclosing loop
+// catches it so it unwinds all the blocks in between.
+type loopSignal struct {
+	keyword string
+}
+
+func (l *loopSignal) Error() string {
+	return l.keyword + " outside of a loop"
+}
+
+var (
+	errBreak    = &loopSignal{"break"}
+	errContinue = &loopSignal{"continue"}
+)
+
+// loopControl checks the result of running a loop body once, it tells if the loop has
+// to stop and with which error. break stops the loop without an error and continue goes on.
+func loopControl(err error) (bool, error) {
+	switch err {
+	case nil, errContinue:
+		return false, nil
+	case errBreak:
+		return true, nil
+	default:
+		return true, err
+	}
+}
+
+func (interp *Interpreter) executeWhileLoop(node *parser.ASTNode, env *Environment) error {
 	cond := node.Children[0]
 	body := node.Children[1]
 
-	left, err := evaluateExpression(cond.Children[0], env)
+	for {
+		if err := interp.tick(node); err != nil {
+			return err
+		}
+		value, err := interp.evaluateExpression(cond, env)
+		if err != nil {
+			return err
+		}
+		if !Truthy(value) {
+			return nil
+		}
 
-	if err != nil {
-		return fmt.Errorf("Error evaluating left hand side ", err)
+		if stop, err := loopControl(interp.executeBlock(body, env)); stop {
+			return err
+		}
 	}
+}
 
-	right, err := evaluateExpression(cond.Children[1], env)
+// evaluateRange evaluates the bounds of range(end), range(start, end) or range(start, end, step),
+// they are evaluated once before the loop starts.
+func (interp *Interpreter) evaluateRange(rangeNode *parser.ASTNode, env *Environment) (start, end, step int, err error) {
+	names := []string{"start", "end", "step"}
+	if len(rangeNode.Children) == 1 {
+		names = []string{"end"}
+	}
 
-	if err != nil {
-		return fmt.Errorf("Error evaluating right hand side ", err)
+	values := make([]int, len(rangeNode.Children))
+	for i, child := range rangeNode.Children {
+		value, err := interp.evaluateExpression(child, env)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		if _, isBig := value.(BigInt); isBig {
+			return 0, 0, 0, errorAt(child, diagnostics.CodeInvalidOperation, "range %s is too large: %s", names[i], value)
+		}
+		bound, ok := value.(Int)
+		if !ok {
+			return 0, 0, 0, errorAt(child, diagnostics.CodeTypeMismatch, "range %s must be an int, got %s", names[i], value.Type())
+		}
+		values[i] = int(bound)
 	}
 
-	operator, ok := cond.Value.(string) // If the thing is ok it return true otherwise false
+	switch len(values) {
+	case 1:
+		return 0, values[0], 1, nil
+	case 2:
+		return values[0], values[1], 1, nil
+	}
 
-	if !ok {
-		return fmt.Errorf("Invalid condition operator: %v", operator)
+	if values[2] == 0 {
+		return 0, 0, 0, errorAt(rangeNode.Children[2], diagnostics.CodeInvalidOperation, "range step must not be zero")
 	}
+	return values[0], values[1], values[2], nil
+}
 
-	var result bool
+func (interp *Interpreter) executeIfStatement(node *parser.ASTNode, env *Environment) error {
 
-	// fmt.Println("left value:", left)
-	// fmt.Println("right value:", right)
-	// fmt.Println("Operator value:", operator)
-	// fmt.Printf("%v -> %T and %v -> %T and %v -> %T \n", left, left, right, right, operator, operator)
+	if len(node.Children) < 2 {
+		return errorAt(node, diagnostics.CodeInvalidOperation, "invalid if statement missing conditon and body")
+	}
 
-	switch operator {
-	case "==":
-		result = left == right
-	case ">":
-		result = left.(int) > right.(int)
-	case "<":
-		result = left.(int) < right.(int)
-	case "!=":
-		result = left != right
-	default:
-		return fmt.Errorf("Unsupported operator: %v", operator)
+	cond := node.Children[0]
+	body := node.Children[1]
+
+	value, err := interp.evaluateExpression(cond, env)
+	if err != nil {
+		return err
+	}
+
+	if Truthy(value) {
+		// The error already carries the location of the failing statement.
+		return interp.executeBlock(body, env)
 	}
 
-	if result {
-		if err := executeBlock(body, env); err != nil {
-			return fmt.Errorf("Error executing in body: %v", err)
+	if len(node.Children) > 2 {
+		elseBranch := node.Children[2]
+		if elseBranch.Type == parser.NodeIfStatement {
+			return interp.executeIfStatement(elseBranch, env)
 		}
+		return interp.executeBlock(elseBranch, env)
 	}
 
 	return nil
 }
 
+// Interpret runs the program in the given environment.
+func (interp *Interpreter) Interpret(ast []*parser.ASTNode, env *Environment) error {
+	return interp.InterpretContext(context.Background(), ast, env)
+}
+
+// InterpretContext runs the program until it ends or the context is done,
+// the step limit counts from the start of every run.
+func (interp *Interpreter) InterpretContext(ctx context.Context, ast []*parser.ASTNode, env *Environment) error {
+	defer interp.setContext(ctx)()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return interp.executeStatement(ast, env)
+}
+
+// EvaluateContext gives the value of an expression, stopping when the context is done.
+func (interp *Interpreter) EvaluateContext(ctx context.Context, node *parser.ASTNode, env *Environment) (Value, error) {
+	defer interp.setContext(ctx)()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return interp.evaluateExpression(node, env)
+}
+
+// setContext uses ctx for the run and starts counting the steps again,
+// it returns the function putting the previous context back.
+func (interp *Interpreter) setContext(ctx context.Context) func() {
+	previous := interp.ctx
+	interp.ctx = ctx
+	interp.steps = 0
+	return func() { interp.ctx = previous }
+}
+
+// Evaluate gives the value of an expression.
+func (interp *Interpreter) Evaluate(node *parser.ASTNode, env *Environment) (Value, error) {
+	return interp.EvaluateContext(context.Background(), node, env)
+}
+
+// Interpret runs the program with the default interpreter settings.
 func Interpret(ast []*parser.ASTNode, env *Environment) error {
-	return executeStatement(ast, env)
+	return NewInterpreter().Interpret(ast, env)
 }
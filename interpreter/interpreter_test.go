@@ -0,0 +1,101 @@
+package interpreter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/AdityaByte/AdiLang/diagnostics"
+	"github.com/AdityaByte/AdiLang/lexer"
+	"github.com/AdityaByte/AdiLang/parser"
+)
+
+// run runs the program and gives what it printed.
+func run(t *testing.T, src string) string {
+	t.Helper()
+	p := parser.Parser{Tokens: lexer.Lexer(src)}
+	nodes, err := p.Parse()
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	var out strings.Builder
+	interp := NewInterpreter()
+	interp.Stdout = &out
+	if err := interp.Interpret(nodes, NewEnvironment(nil)); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	return out.String()
+}
+
+func TestLoopClosuresKeepTheirIteration(t *testing.T) {
+	tests := []struct {
+		name string
+		loop string
+		want string
+	}{
+		{"range", `fordude i in range(3) { append(fs, fundude() { return i }) }`, "[0, 1, 2]\n"},
+		{"list", `fordude x in ["a", "b"] { append(fs, fundude() { return x }) }`, `["a", "b"]` + "\n"},
+		{"list index", `fordude i, x in ["a", "b"] { append(fs, fundude() { return i }) }`, "[0, 1]\n"},
+		{"map", `fordude k, v in {"a": 1, "b": 2} { append(fs, fundude() { return v }) }`, "[1, 2]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := "var(fs = [])\n" + tt.loop + "\n" +
+				"var(results = [])\nfordude f in fs { append(results, f()) }\nout->results\n"
+			if got := run(t, src); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCallDepthIsClamped(t *testing.T) {
+	tests := []struct {
+		maxCallDepth int
+		want         int
+	}{
+		{0, DefaultMaxCallDepth},
+		{-1, DefaultMaxCallDepth},
+		{10, 10},
+		{100_000_000, MaxCallDepthLimit},
+	}
+	for _, tt := range tests {
+		p := parser.Parser{Tokens: lexer.Lexer("fundude f(n) { return f(n + 1) }\nf(0)\n")}
+		nodes, err := p.Parse()
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		interp := NewInterpreter()
+		interp.MaxCallDepth = tt.maxCallDepth
+		err = interp.Interpret(nodes, NewEnvironment(nil))
+		var depth *CallDepthError
+		if !errors.As(err, &depth) || depth.Limit != tt.want {
+			t.Errorf("MaxCallDepth %d: got %v, want a call depth error with limit %d", tt.maxCallDepth, err, tt.want)
+		}
+	}
+}
+
+func TestBigIntArguments(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`repeat("a", 99999999999999999999)`, "repeat argument is too large: 99999999999999999999"},
+		{`substr("abc", 0, 99999999999999999999)`, "substr argument is too large: 99999999999999999999"},
+		{"math.random(99999999999999999999)", "math.random argument is too large: 99999999999999999999"},
+		{"math.random(-99999999999999999999)", "math.random expects a positive int, got -99999999999999999999"},
+		{"math.seed(99999999999999999999)", "math.seed argument is too large: 99999999999999999999"},
+	}
+	for _, tt := range tests {
+		p := parser.Parser{Tokens: lexer.Lexer(tt.src)}
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.src, err)
+		}
+		_, err = NewInterpreter().Evaluate(expr, NewEnvironment(nil))
+		var d *diagnostics.Diagnostic
+		if !errors.As(err, &d) || d.Message != tt.want {
+			t.Errorf("%s: error = %v, want %q", tt.src, err, tt.want)
+		}
+	}
+}
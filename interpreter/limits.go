@@ -0,0 +1,201 @@
+package interpreter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AdityaByte/AdiLang/diagnostics"
+	"github.com/AdityaByte/AdiLang/parser"
+)
+
+// The limits of a program are checked while it runs, a limit of 0 means no limit. Every limit
+// has its own error type, it is the Cause of the diagnostic so errors.As finds it.
+
+// StepLimitError stops a program which ran more steps than MaxSteps, a step is a statement,
+// a loop iteration or a call.
+type StepLimitError struct {
+	Limit int64
+}
+
+func (e *StepLimitError) Error() string {
+	return fmt.Sprintf("step limit of %d exceeded", e.Limit)
+}
+
+// TimeoutError is the cause of a context which stops the program after its time is up,
+// it is also a context.DeadlineExceeded for errors.Is.
+type TimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("timeout of %v exceeded", e.Timeout)
+}
+
+func (e *TimeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+// CallDepthError stops a program with more than MaxCallDepth nested calls.
+type CallDepthError struct {
+	Limit int
+}
+
+func (e *CallDepthError) Error() string {
+	return fmt.Sprintf("call depth limit of %d exceeded", e.Limit)
+}
+
+// StringSizeError stops a program making a string longer than MaxStringSize bytes.
+type StringSizeError struct {
+	Size, Limit int
+}
+
+func (e *StringSizeError) Error() string {
+	return fmt.Sprintf("string of %d bytes exceeds the limit of %d", e.Size, e.Limit)
+}
+
+// ListSizeError stops a program making a list with more than MaxListSize elements.
+type ListSizeError struct {
+	Size, Limit int
+}
+
+func (e *ListSizeError) Error() string {
+	return fmt.Sprintf("list of %d elements exceeds the limit of %d", e.Size, e.Limit)
+}
+
+// IntSizeError stops a program making an int with more than MaxIntBits bits.
+type IntSizeError struct {
+	Bits, Limit int
+}
+
+func (e *IntSizeError) Error() string {
+	return fmt.Sprintf("int of %d bits exceeds the limit of %d", e.Bits, e.Limit)
+}
+
+// Capability is a set of things outside the program a builtin can touch, the builtins which
+// need one only work when the interpreter was granted it.
+type Capability int
+
+const (
+	CapFilesystem Capability = 1 << iota // the fs module
+	CapEnv                               // the env module
+	CapProcess                           // the process module
+
+	CapNone Capability = 0
+	CapAll             = CapFilesystem | CapEnv | CapProcess
+)
+
+var capabilityNames = []struct {
+	capability Capability
+	name       string
+}{
+	{CapFilesystem, "filesystem"},
+	{CapEnv, "env"},
+	{CapProcess, "process"},
+}
+
+func (c Capability) String() string {
+	var names []string
+	for _, n := range capabilityNames {
+		if c&n.capability != 0 {
+			names = append(names, n.name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ",")
+}
+
+// ParseCapabilities reads a list like "filesystem,env", "all" or "none". The fs module
+// can also be given as fs.
+func ParseCapabilities(list string) (Capability, error) {
+	var result Capability
+	for _, name := range strings.Split(list, ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "all":
+			result |= CapAll
+		case "none", "":
+		case "fs", "filesystem":
+			result |= CapFilesystem
+		case "env":
+			result |= CapEnv
+		case "process":
+			result |= CapProcess
+		default:
+			return 0, fmt.Errorf("unknown capability %q", name)
+		}
+	}
+	return result, nil
+}
+
+// PermissionError stops a program calling a builtin which needs a capability that was not granted.
+type PermissionError struct {
+	Name       string
+	Capability Capability
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("%s needs the %s capability, which was not granted", e.Name, e.Capability)
+}
+
+// limitError creates the diagnostic of a limit with the limit error as the cause.
+func limitError(node *parser.ASTNode, code string, cause error) *diagnostics.Diagnostic {
+	d := errorAt(node, code, "%v", cause)
+	d.Cause = cause
+	return d
+}
+
+// tick counts a step of the program, it stops the program when it ran too many steps
+// or when its context is done.
+func (interp *Interpreter) tick(node *parser.ASTNode) error {
+	interp.steps++
+	if interp.MaxSteps > 0 && interp.steps > interp.MaxSteps {
+		return limitError(node, diagnostics.CodeStepLimit, &StepLimitError{Limit: interp.MaxSteps})
+	}
+
+	if interp.ctx == nil {
+		return nil
+	}
+	if err := interp.ctx.Err(); err != nil {
+		d := errorAt(node, diagnostics.CodeCancelled, "execution stopped: %v", context.Cause(interp.ctx))
+		d.Cause = context.Cause(interp.ctx)
+		return d
+	}
+	return nil
+}
+
+// checkSize stops the program when the value is a string or a list over the size limits.
+func (interp *Interpreter) checkSize(node *parser.ASTNode, value Value) error {
+	switch v := value.(type) {
+	case String:
+		return interp.checkStringSize(node, len(v))
+	case *List:
+		return interp.checkListSize(node, len(v.Elements))
+	case BigInt:
+		return interp.checkIntSize(node, v.value.BitLen())
+	}
+	return nil
+}
+
+func (interp *Interpreter) checkIntSize(node *parser.ASTNode, bits int) error {
+	if interp.MaxIntBits > 0 && bits > interp.MaxIntBits {
+		return limitError(node, diagnostics.CodeSizeLimit, &IntSizeError{Bits: bits, Limit: interp.MaxIntBits})
+	}
+	return nil
+}
+
+func (interp *Interpreter) checkStringSize(node *parser.ASTNode, size int) error {
+	if interp.MaxStringSize > 0 && size > interp.MaxStringSize {
+		return limitError(node, diagnostics.CodeSizeLimit, &StringSizeError{Size: size, Limit: interp.MaxStringSize})
+	}
+	return nil
+}
+
+func (interp *Interpreter) checkListSize(node *parser.ASTNode, size int) error {
+	if interp.MaxListSize > 0 && size > interp.MaxListSize {
+		return limitError(node, diagnostics.CodeSizeLimit, &ListSizeError{Size: size, Limit: interp.MaxListSize})
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package interpreter
+
+import (
+	"strings"
+
+	"github.com/AdityaByte/AdiLang/diagnostics"
+	"github.com/AdityaByte/AdiLang/parser"
+)
+
+// List is the list value of the language, it is shared by reference so
+// append on a list is seen by every variable holding it.
+type List struct {
+	Elements []Value
+}
+
+func (l *List) Type() string { return "list" }
+
+func (l *List) String() string {
+	return l.format(nil)
+}
+
+func (l *List) format(seen map[Value]bool) string {
+	if seen[l] {
+		return "[...]"
+	}
+	if seen == nil {
+		seen = map[Value]bool{}
+	}
+	seen[l] = true
+	defer delete(seen, l)
+
+	parts := make([]string, len(l.Elements))
+	for i, element := range l.Elements {
+		parts[i] = inspect(element, seen)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// resolveIndex turns a negative index into one counted from the end and checks the bounds.
+func resolveIndex(node *parser.ASTNode, index, length int) (int, error) {
+	original := index
+	if index < 0 {
+		index += length
+	}
+	if index < 0 || index >= length {
+		return 0, errorAt(node, diagnostics.CodeIndexOutOfRange, "index %d out of range for length %d", original, length)
+	}
+	return index, nil
+}
+
+// sliceBounds works out the bounds of a slice, like in python they are
+// clamped to the length instead of failing.
+func sliceBounds(start, end *int, length int) (int, int) {
+	clamp := func(bound *int, fallback int) int {
+		if bound == nil {
+			return fallback
+		}
+		value := *bound
+		if value < 0 {
+			value += length
+		}
+		return max(0, min(value, length))
+	}
+
+	from, to := clamp(start, 0), clamp(end, length)
+	if to < from {
+		to = from
+	}
+	return from, to
+}
+
+func (interp *Interpreter) evaluateListLiteral(node *parser.ASTNode, env *Environment) (Value, error) {
+	elements := make([]Value, len(node.Children))
+	for i, child := range node.Children {
+		value, err := interp.evaluateExpression(child, env)
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = value
+	}
+	if err := interp.checkListSize(node, len(elements)); err != nil {
+		return nil, err
+	}
+	return &List{Elements: elements}, nil
+}
+
+func (interp *Interpreter) evaluateIndex(node *parser.ASTNode, env *Environment) (Value, error) {
+	target, err := interp.evaluateExpression(node.Children[0], env)
+	if err != nil {
+		return nil, err
+	}
+	indexNode := node.Children[1]
+	index, err := interp.evaluateExpression(indexNode, env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := target.(type) {
+	case *Map:
+		return mapGet(indexNode, t, index)
+	case *List:
+		i, err := listIndex(indexNode, index, len(t.Elements))
+		if err != nil {
+			return nil, err
+		}
+		return t.Elements[i], nil
+	case String:
+		chars := []rune(string(t))
+		i, err := listIndex(indexNode, index, len(chars))
+		if err != nil {
+			return nil, err
+		}
+		return String(chars[i]), nil
+	default:
+		return nil, errorAt(node.Children[0], diagnostics.CodeTypeMismatch, "cannot index a value of type %s", target.Type())
+	}
+}
+
+// listIndex checks that the index of a list or string is an int within the bounds.
+func listIndex(node *parser.ASTNode, index Value, length int) (int, error) {
+	if _, isBig := index.(BigInt); isBig {
+		return 0, errorAt(node, diagnostics.CodeIndexOutOfRange, "index %s out of range for length %d", index, length)
+	}
+	i, ok := index.(Int)
+	if !ok {
+		return 0, errorAt(node, diagnostics.CodeTypeMismatch, "index must be an int, got %s", index.Type())
+	}
+	return resolveIndex(node, int(i), length)
+}
+
+func (interp *Interpreter) evaluateSlice(node *parser.ASTNode, env *Environment) (Value, error) {
+	target, err := interp.evaluateExpression(node.Children[0], env)
+	if err != nil {
+		return nil, err
+	}
+
+	var bounds [2]*int
+	for i, child := range node.Children[1:] {
+		if child == nil {
+			continue
+		}
+		bound, err := interp.evaluateInt(child, env, "slice bound")
+		if err != nil {
+			return nil, err
+		}
+		bounds[i] = &bound
+	}
+
+	switch t := target.(type) {
+	case *List:
+		from, to := sliceBounds(bounds[0], bounds[1], len(t.Elements))
+		elements := make([]Value, to-from)
+		copy(elements, t.Elements[from:to])
+		return &List{Elements: elements}, nil
+	case String:
+		chars := []rune(string(t))
+		from, to := sliceBounds(bounds[0], bounds[1], len(chars))
+		return String(chars[from:to]), nil
+	default:
+		return nil, errorAt(node.Children[0], diagnostics.CodeTypeMismatch, "cannot slice a value of type %s", target.Type())
+	}
+}
+
+// assignIndex stores the value at xs[i] or m[key], the compound operators use the current element.
+func (interp *Interpreter) assignIndex(node *parser.ASTNode, env *Environment, operator string, value Value) error {
+	target := node.Children[0]
+	container, err := interp.evaluateExpression(target.Children[0], env)
+	if err != nil {
+		return err
+	}
+	indexNode := target.Children[1]
+	index, err := interp.evaluateExpression(indexNode, env)
+	if err != nil {
+		return err
+	}
+
+	// current reads the element for the compound operators.
+	var current func() (Value, error)
+	var store func(value Value)
+
+	switch c := container.(type) {
+	case *List:
+		i, err := listIndex(indexNode, index, len(c.Elements))
+		if err != nil {
+			return err
+		}
+		current = func() (Value, error) { return c.Elements[i], nil }
+		store = func(value Value) { c.Elements[i] = value }
+	case *Map:
+		if err := checkKey(indexNode, index); err != nil {
+			return err
+		}
+		current = func() (Value, error) { return mapGet(indexNode, c, index) }
+		store = func(value Value) { c.Set(index, value) }
+	default:
+		return errorAt(target.Children[0], diagnostics.CodeTypeMismatch, "cannot assign to an index of type %s", container.Type())
+	}
+
+	if operator != "=" {
+		old, err := current()
+		if err != nil {
+			return err
+		}
+		value, err = evaluateBinaryOperation(node, strings.TrimSuffix(operator, "="), old, value)
+		if err != nil {
+			return err
+		}
+		if err := interp.checkSize(node, value); err != nil {
+			return err
+		}
+	}
+	store(value)
+	return nil
+}
+
+// evaluateInt evaluates an expression which must give an int, what is used in the error message.
+func (interp *Interpreter) evaluateInt(node *parser.ASTNode, env *Environment, what string) (int, error) {
+	value, err := interp.evaluateExpression(node, env)
+	if err != nil {
+		return 0, err
+	}
+	if _, isBig := value.(BigInt); isBig {
+		return 0, errorAt(node, diagnostics.CodeInvalidOperation, "%s is too large: %s", what, value)
+	}
+	result, ok := value.(Int)
+	if !ok {
+		return 0, errorAt(node, diagnostics.CodeTypeMismatch, "%s must be an int, got %s", what, value.Type())
+	}
+	return int(result), nil
+}
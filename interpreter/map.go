@@ -0,0 +1,136 @@
+package interpreter
+
+import (
+	"strings"
+
+	"github.com/AdityaByte/AdiLang/diagnostics"
+	"github.com/AdityaByte/AdiLang/parser"
+)
+
+// Map is the dictionary value of the language. The keys are kept in the order they
+// were added so printing and iterating a map gives the same output on every run.
+type Map struct {
+	keys   []Value
+	values map[interface{}]Value
+}
+
+func NewMap() *Map {
+	return &Map{values: make(map[interface{}]Value)}
+}
+
+// hashKey gives what the key is stored under in the Go map. A big int is a pointer
+// so two equal big ints are turned into the same string to find the same entry.
+func hashKey(key Value) interface{} {
+	if b, ok := key.(BigInt); ok {
+		return bigKey(b.value.String())
+	}
+	return key
+}
+
+type bigKey string
+
+func (m *Map) Get(key Value) (Value, bool) {
+	value, ok := m.values[hashKey(key)]
+	return value, ok
+}
+
+func (m *Map) Set(key, value Value) {
+	hash := hashKey(key)
+	if _, exists := m.values[hash]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[hash] = value
+}
+
+// Delete removes the key and reports if it was there.
+func (m *Map) Delete(key Value) bool {
+	hash := hashKey(key)
+	if _, exists := m.values[hash]; !exists {
+		return false
+	}
+	delete(m.values, hash)
+	for i, k := range m.keys {
+		if hashKey(k) == hash {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// Keys returns a copy of the keys in insertion order.
+func (m *Map) Keys() []Value {
+	keys := make([]Value, len(m.keys))
+	copy(keys, m.keys)
+	return keys
+}
+
+func (m *Map) Type() string { return "map" }
+
+func (m *Map) Len() int {
+	return len(m.keys)
+}
+
+func (m *Map) String() string {
+	return m.format(nil)
+}
+
+func (m *Map) format(seen map[Value]bool) string {
+	if seen[m] {
+		return "{...}"
+	}
+	if seen == nil {
+		seen = map[Value]bool{}
+	}
+	seen[m] = true
+	defer delete(seen, m)
+
+	parts := make([]string, len(m.keys))
+	for i, key := range m.keys {
+		parts[i] = Inspect(key) + ": " + inspect(m.values[hashKey(key)], seen)
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// checkKey makes sure the value can be used as a map key, only the
+// values compared by their content can be keys.
+func checkKey(node *parser.ASTNode, key Value) error {
+	switch key.(type) {
+	case Int, BigInt, String, Bool:
+		return nil
+	default:
+		return errorAt(node, diagnostics.CodeTypeMismatch, "a %s can not be used as a map key", key.Type())
+	}
+}
+
+func (interp *Interpreter) evaluateMapLiteral(node *parser.ASTNode, env *Environment) (Value, error) {
+	m := NewMap()
+	for i := 0; i+1 < len(node.Children); i += 2 {
+		key, err := interp.evaluateExpression(node.Children[i], env)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkKey(node.Children[i], key); err != nil {
+			return nil, err
+		}
+
+		value, err := interp.evaluateExpression(node.Children[i+1], env)
+		if err != nil {
+			return nil, err
+		}
+		m.Set(key, value)
+	}
+	return m, nil
+}
+
+// mapGet reads m[key], a missing key is an error, has(m, key) can be used to check it first.
+func mapGet(node *parser.ASTNode, m *Map, key Value) (Value, error) {
+	if err := checkKey(node, key); err != nil {
+		return nil, err
+	}
+	value, ok := m.Get(key)
+	if !ok {
+		return nil, errorAt(node, diagnostics.CodeKeyNotFound, "key not found: %s", Inspect(key))
+	}
+	return value, nil
+}
@@ -0,0 +1,259 @@
+package interpreter
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/AdityaByte/AdiLang/diagnostics"
+	"github.com/AdityaByte/AdiLang/parser"
+)
+
+func init() {
+	members := map[string]Value{
+		"PI": Float(math.Pi),
+		"E":  Float(math.E),
+	}
+	add := func(name string, arity int, fn func(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error)) {
+		members[name] = &Builtin{Name: "math." + name, Arity: arity, Fn: fn}
+	}
+
+	add("abs", 1, mathAbs)
+	add("min", -1, mathMin)
+	add("max", -1, mathMax)
+	add("pow", 2, mathPow)
+	add("sqrt", 1, mathSqrt)
+	add("floor", 1, roundingFunc("floor", math.Floor))
+	add("ceil", 1, roundingFunc("ceil", math.Ceil))
+	add("round", 1, roundingFunc("round", math.Round))
+	add("sin", 1, floatFunc("sin", math.Sin))
+	add("cos", 1, floatFunc("cos", math.Cos))
+	add("tan", 1, floatFunc("tan", math.Tan))
+	add("log", -1, mathLog)
+	add("divmod", 2, mathDivmod)
+	add("random", -1, mathRandom)
+	add("seed", 1, mathSeed)
+
+	registerModule("math", members)
+}
+
+func numberArg(node *parser.ASTNode, name string, args []Value, i int) (Float, error) {
+	f, ok := toFloat(args[i])
+	if !ok {
+		return 0, argError(node, i, "math.%s expects a number, got %s", name, args[i].Type())
+	}
+	return f, nil
+}
+
+// floatFunc makes a math function of one number which always gives a float.
+func floatFunc(name string, fn func(float64) float64) func(*Interpreter, *parser.ASTNode, []Value) (Value, error) {
+	return func(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+		x, err := numberArg(node, name, args, 0)
+		if err != nil {
+			return nil, err
+		}
+		return Float(fn(float64(x))), nil
+	}
+}
+
+// roundingFunc makes floor, ceil and round, they give an int and leave the ints as they are.
+func roundingFunc(name string, fn func(float64) float64) func(*Interpreter, *parser.ASTNode, []Value) (Value, error) {
+	return func(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+		if _, ok := toBig(args[0]); ok {
+			return args[0], nil
+		}
+		x, err := numberArg(node, name, args, 0)
+		if err != nil {
+			return nil, err
+		}
+		return floatToInt(node, Float(fn(float64(x))))
+	}
+}
+
+// floatToInt converts a float without a fraction to an int, a big int when it does not fit in 64 bits.
+func floatToInt(node *parser.ASTNode, f Float) (Value, error) {
+	if math.IsInf(float64(f), 0) || math.IsNaN(float64(f)) {
+		return nil, errorAt(node, diagnostics.CodeInvalidOperation, "cannot convert %s to an int", f)
+	}
+	if f >= math.MinInt64 && f < math.MaxInt64 {
+		return Int(f), nil
+	}
+	result, _ := big.NewFloat(float64(f)).Int(nil)
+	return normalizeBig(result), nil
+}
+
+func mathAbs(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	switch x := args[0].(type) {
+	case Int, BigInt:
+		value, _ := toBig(x)
+		return normalizeBig(new(big.Int).Abs(value)), nil
+	case Float:
+		return Float(math.Abs(float64(x))), nil
+	default:
+		return nil, argError(node, 0, "math.abs expects a number, got %s", x.Type())
+	}
+}
+
+// mathMin and mathMax take the values as arguments or a single list of them.
+func mathMin(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	return pickValue(node, "min", args, func(order int) bool { return order < 0 })
+}
+
+func mathMax(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	return pickValue(node, "max", args, func(order int) bool { return order > 0 })
+}
+
+// pickValue gives the value which is better than all the others, better tells it from the
+// comparison of a value with the best one so far.
+func pickValue(node *parser.ASTNode, name string, args []Value, better func(order int) bool) (Value, error) {
+	values := args
+	if len(args) == 1 {
+		if list, ok := args[0].(*List); ok {
+			values = list.Elements
+		}
+	}
+	if len(values) == 0 {
+		return nil, errorAt(node, diagnostics.CodeInvalidOperation, "math.%s expects at least one value", name)
+	}
+
+	best := values[0]
+	for _, value := range values[1:] {
+		order, err := compare(node, value, best)
+		if err != nil {
+			return nil, err
+		}
+		if better(order) {
+			best = value
+		}
+	}
+	return best, nil
+}
+
+// pow(x, y) is exact for ints with a positive exponent, everything else is computed with floats.
+func mathPow(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	base, baseIsInt := toBig(args[0])
+	exponent, exponentIsInt := toBig(args[1])
+	if baseIsInt && exponentIsInt && exponent.Sign() >= 0 {
+		// The size of the result is checked before it is computed, Exp can not be stopped.
+		if bits := powBits(base, exponent); interp.MaxIntBits > 0 && bits > float64(interp.MaxIntBits) {
+			return nil, limitError(node, diagnostics.CodeSizeLimit, &IntSizeError{Bits: int(min(bits, math.MaxInt)), Limit: interp.MaxIntBits})
+		}
+		return normalizeBig(new(big.Int).Exp(base, exponent, nil)), nil
+	}
+
+	x, err := numberArg(node, "pow", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	y, err := numberArg(node, "pow", args, 1)
+	if err != nil {
+		return nil, err
+	}
+	return Float(math.Pow(float64(x), float64(y))), nil
+}
+
+// powBits gives about how many bits base ** exponent has, the powers of 0, 1 and -1 are small.
+func powBits(base, exponent *big.Int) float64 {
+	abs := new(big.Int).Abs(base)
+	if abs.Cmp(big.NewInt(1)) <= 0 {
+		return 1
+	}
+	// log2(base) from the mantissa and exponent, the base can be too big for a float64.
+	mantissa := new(big.Float)
+	exp := new(big.Float).SetInt(abs).MantExp(mantissa)
+	m, _ := mantissa.Float64()
+	e, _ := new(big.Float).SetInt(exponent).Float64()
+	return (float64(exp) + math.Log2(m)) * e
+}
+
+func mathSqrt(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	x, err := numberArg(node, "sqrt", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	if x < 0 {
+		return nil, argError(node, 0, "math.sqrt of a negative number: %s", args[0])
+	}
+	return Float(math.Sqrt(float64(x))), nil
+}
+
+// log(x) is the natural logarithm, log(x, base) the logarithm in the given base.
+func mathLog(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, errorAt(node, diagnostics.CodeInvalidOperation, "math.log expects a number and an optional base")
+	}
+	x, err := numberArg(node, "log", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	if x <= 0 {
+		return nil, argError(node, 0, "math.log of a number which is not positive: %s", args[0])
+	}
+	if len(args) == 1 {
+		return Float(math.Log(float64(x))), nil
+	}
+
+	base, err := numberArg(node, "log", args, 1)
+	if err != nil {
+		return nil, err
+	}
+	if base <= 0 || base == 1 {
+		return nil, argError(node, 1, "invalid base for math.log: %s", args[1])
+	}
+	return Float(math.Log(float64(x)) / math.Log(float64(base))), nil
+}
+
+// divmod(a, b) gives [a / b, a % b] for two ints.
+func mathDivmod(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	for i := range args {
+		if _, ok := toBig(args[i]); !ok {
+			return nil, argError(node, i, "math.divmod expects an int, got %s", args[i].Type())
+		}
+	}
+	quotient, err := evaluateBinaryOperation(node, "/", args[0], args[1])
+	if err != nil {
+		return nil, err
+	}
+	remainder, err := evaluateBinaryOperation(node, "%", args[0], args[1])
+	if err != nil {
+		return nil, err
+	}
+	return &List{Elements: []Value{quotient, remainder}}, nil
+}
+
+// random() gives a float from 0 up to 1 and random(n) an int from 0 up to n, without 1 and n.
+func mathRandom(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	switch len(args) {
+	case 0:
+		return Float(interp.random.Float64()), nil
+	case 1:
+		if n, isBig := args[0].(BigInt); isBig {
+			if n.value.Sign() < 0 {
+				return nil, argError(node, 0, "math.random expects a positive int, got %s", n)
+			}
+			return nil, tooLargeArg(node, 0, "math.random", n)
+		}
+		n, ok := args[0].(Int)
+		if !ok {
+			return nil, argError(node, 0, "math.random expects an int, got %s", args[0].Type())
+		}
+		if n <= 0 {
+			return nil, argError(node, 0, "math.random expects a positive int, got %d", n)
+		}
+		return Int(interp.random.Int63n(int64(n))), nil
+	default:
+		return nil, errorAt(node, diagnostics.CodeInvalidOperation, "math.random expects no arguments or an int")
+	}
+}
+
+// seed(n) restarts the random numbers from the seed, like the --seed flag.
+func mathSeed(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	if _, isBig := args[0].(BigInt); isBig {
+		return nil, tooLargeArg(node, 0, "math.seed", args[0])
+	}
+	seed, ok := args[0].(Int)
+	if !ok {
+		return nil, argError(node, 0, "math.seed expects an int, got %s", args[0].Type())
+	}
+	interp.Seed(int64(seed))
+	return Nil{}, nil
+}
@@ -0,0 +1,45 @@
+package interpreter
+
+import (
+	"fmt"
+
+	"github.com/AdityaByte/AdiLang/diagnostics"
+	"github.com/AdityaByte/AdiLang/parser"
+)
+
+// Module groups builtins and constants under a name, like math.sqrt and math.PI.
+type Module struct {
+	Name    string
+	Members map[string]Value
+}
+
+func (m *Module) Type() string { return "module" }
+
+func (m *Module) String() string {
+	return fmt.Sprintf("<module %s>", m.Name)
+}
+
+// modules are looked up like the builtins when a name is not found in the environment.
+var modules = map[string]*Module{}
+
+func registerModule(name string, members map[string]Value) {
+	modules[name] = &Module{Name: name, Members: members}
+}
+
+func (interp *Interpreter) evaluateMember(node *parser.ASTNode, env *Environment) (Value, error) {
+	target, err := interp.evaluateExpression(node.Children[0], env)
+	if err != nil {
+		return nil, err
+	}
+	name := node.Value.(string)
+
+	module, ok := target.(*Module)
+	if !ok {
+		return nil, errorAt(node.Children[0], diagnostics.CodeTypeMismatch, "cannot access .%s on a value of type %s", name, target.Type())
+	}
+	member, ok := module.Members[name]
+	if !ok {
+		return nil, errorAt(node, diagnostics.CodeUndefinedVariable, "module %s has no member %s", module.Name, name)
+	}
+	return member, nil
+}
@@ -0,0 +1,57 @@
+package interpreter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AdityaByte/AdiLang/diagnostics"
+	"github.com/AdityaByte/AdiLang/parser"
+)
+
+// ArgumentError is returned by a native function when an argument is wrong, the error
+// then points at that argument of the call.
+type ArgumentError struct {
+	Index int // starts at 0
+	Err   error
+}
+
+func (e *ArgumentError) Error() string {
+	return fmt.Sprintf("argument %d: %v", e.Index+1, e.Err)
+}
+
+func (e *ArgumentError) Unwrap() error {
+	return e.Err
+}
+
+// NewNative makes a builtin of a go function provided by the program embedding the
+// interpreter. The function does not see the syntax tree so its errors are turned into
+// diagnostics at the call, the go error stays available as their cause.
+func NewNative(name string, arity int, fn func(args []Value) (Value, error)) *Builtin {
+	return &Builtin{Name: name, Arity: arity, Fn: func(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+		value, err := fn(args)
+		if err != nil {
+			return nil, nativeError(node, name, err)
+		}
+		if value == nil {
+			return Nil{}, nil
+		}
+		return value, nil
+	}}
+}
+
+func nativeError(node *parser.ASTNode, name string, err error) error {
+	var diag *diagnostics.Diagnostic
+	if errors.As(err, &diag) {
+		return diag
+	}
+
+	var d *diagnostics.Diagnostic
+	var argErr *ArgumentError
+	if errors.As(err, &argErr) {
+		d = argError(node, argErr.Index, "%v", argErr.Err)
+	} else {
+		d = errorAt(node, diagnostics.CodeNativeError, "%s: %v", name, err)
+	}
+	d.Cause = err
+	return d
+}
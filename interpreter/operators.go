@@ -0,0 +1,238 @@
+package interpreter
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/AdityaByte/AdiLang/diagnostics"
+	"github.com/AdityaByte/AdiLang/parser"
+)
+
+// operatorVerbs name the arithmetic operators in the type mismatch errors, like "cannot add string and int".
+var operatorVerbs = map[string]string{
+	"+": "add",
+	"-": "subtract",
+	"*": "multiply",
+	"/": "divide",
+	"%": "take the modulo of",
+}
+
+// evaluateBinaryOperation applies the operator on the two values, node is only used for the error location.
+func evaluateBinaryOperation(node *parser.ASTNode, operator string, left, right Value) (Value, error) {
+	switch operator {
+	case "==":
+		return Bool(Equal(left, right)), nil
+	case "!=":
+		return Bool(!Equal(left, right)), nil
+	case "<", ">", "<=", ">=":
+		return compareValues(node, operator, left, right)
+	}
+
+	// An int with an int stays an int, as soon as one of the numbers is a float both are used as floats.
+	if l, ok := left.(Int); ok {
+		if r, ok := right.(Int); ok {
+			return evaluateIntOperation(node, operator, l, r)
+		}
+	}
+	if l, ok := toBig(left); ok {
+		if r, ok := toBig(right); ok {
+			return evaluateBigOperation(node, operator, l, r)
+		}
+	}
+	if l, ok := toFloat(left); ok {
+		if r, ok := toFloat(right); ok {
+			return evaluateFloatOperation(node, operator, l, r)
+		}
+	}
+
+	switch l := left.(type) {
+	case String:
+		if r, ok := right.(String); ok && operator == "+" {
+			return l + r, nil
+		}
+	case *List:
+		if r, ok := right.(*List); ok && operator == "+" {
+			elements := make([]Value, 0, len(l.Elements)+len(r.Elements))
+			elements = append(elements, l.Elements...)
+			elements = append(elements, r.Elements...)
+			return &List{Elements: elements}, nil
+		}
+	}
+
+	verb, ok := operatorVerbs[operator]
+	if !ok {
+		return nil, errorAt(node, diagnostics.CodeInvalidOperation, "Unsupported operator: %v", operator)
+	}
+	return nil, errorAt(node, diagnostics.CodeTypeMismatch, "cannot %s %s and %s", verb, left.Type(), right.Type())
+}
+
+// evaluateIntOperation works on 64 bit ints and moves to big ints when the result does not fit.
+func evaluateIntOperation(node *parser.ASTNode, operator string, left, right Int) (Value, error) {
+	switch operator {
+	case "+":
+		if sum := left + right; (right >= 0) == (sum >= left) {
+			return sum, nil
+		}
+	case "-":
+		if diff := left - right; (right >= 0) == (diff <= left) {
+			return diff, nil
+		}
+	case "*":
+		product := left * right
+		if left == 0 || (product/left == right && !(left == -1 && right == math.MinInt64)) {
+			return product, nil
+		}
+	case "/", "%":
+		if right == 0 {
+			return nil, errorAt(node, diagnostics.CodeInvalidOperation, "division by zero")
+		}
+		// The only division which overflows is the smallest int divided by -1.
+		if left != math.MinInt64 || right != -1 {
+			if operator == "/" {
+				return left / right, nil
+			}
+			return left % right, nil
+		}
+	default:
+		return nil, errorAt(node, diagnostics.CodeInvalidOperation, "Unsupported operator: %v", operator)
+	}
+	return evaluateBigOperation(node, operator, big.NewInt(int64(left)), big.NewInt(int64(right)))
+}
+
+func evaluateFloatOperation(node *parser.ASTNode, operator string, left, right Float) (Value, error) {
+	switch operator {
+	case "+":
+		return left + right, nil
+	case "-":
+		return left - right, nil
+	case "*":
+		return left * right, nil
+	case "/":
+		if right == 0 {
+			return nil, errorAt(node, diagnostics.CodeInvalidOperation, "division by zero")
+		}
+		return left / right, nil
+	case "%":
+		if right == 0 {
+			return nil, errorAt(node, diagnostics.CodeInvalidOperation, "division by zero")
+		}
+		return Float(math.Mod(float64(left), float64(right))), nil
+	default:
+		return nil, errorAt(node, diagnostics.CodeInvalidOperation, "Unsupported operator: %v", operator)
+	}
+}
+
+// toFloat gives the value of an int or float as a float.
+func toFloat(value Value) (Float, bool) {
+	switch v := value.(type) {
+	case Int:
+		return Float(v), true
+	case Float:
+		return v, true
+	case BigInt:
+		return bigToFloat(v.value), true
+	}
+	return 0, false
+}
+
+// compareValues handles < > <= >=, only numbers and strings can be ordered.
+func compareValues(node *parser.ASTNode, operator string, left, right Value) (Value, error) {
+	order, err := compare(node, left, right)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operator {
+	case "<":
+		return Bool(order < 0), nil
+	case ">":
+		return Bool(order > 0), nil
+	case "<=":
+		return Bool(order <= 0), nil
+	default:
+		return Bool(order >= 0), nil
+	}
+}
+
+// compare gives -1, 0 or 1 like strings.Compare, an int and a float are compared as floats.
+func compare(node *parser.ASTNode, left, right Value) (int, error) {
+	if l, ok := left.(Int); ok {
+		if r, ok := right.(Int); ok {
+			return compareOrdered(l, r), nil
+		}
+	}
+	if l, ok := toBig(left); ok {
+		if r, ok := toBig(right); ok {
+			return l.Cmp(r), nil
+		}
+	}
+	if l, ok := toFloat(left); ok {
+		if r, ok := toFloat(right); ok {
+			return compareOrdered(l, r), nil
+		}
+	}
+	if l, ok := left.(String); ok {
+		if r, ok := right.(String); ok {
+			return compareOrdered(l, r), nil
+		}
+	}
+	return 0, compareError(node, left, right)
+}
+
+func compareOrdered[T Int | Float | String](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareError(node *parser.ASTNode, left, right Value) error {
+	return errorAt(node, diagnostics.CodeTypeMismatch, "cannot compare %s and %s", left.Type(), right.Type())
+}
+
+func evaluateUnaryOperation(node *parser.ASTNode, operand Value) (Value, error) {
+	switch node.Value.(string) {
+	case "-":
+		switch value := operand.(type) {
+		case Int:
+			if value == math.MinInt64 {
+				return normalizeBig(new(big.Int).Neg(big.NewInt(int64(value)))), nil
+			}
+			return -value, nil
+		case BigInt:
+			return normalizeBig(new(big.Int).Neg(value.value)), nil
+		case Float:
+			return -value, nil
+		}
+	case "!":
+		return Bool(!Truthy(operand)), nil
+	}
+	return nil, errorAt(node, diagnostics.CodeTypeMismatch, "cannot negate %s", operand.Type())
+}
+
+// evaluateLogicalOperation evaluates && and ||, the right side is only evaluated
+// when the left side does not already decide the result.
+func (interp *Interpreter) evaluateLogicalOperation(node *parser.ASTNode, env *Environment) (Value, error) {
+	operator := node.Value.(string)
+
+	left, err := interp.evaluateExpression(node.Children[0], env)
+	if err != nil {
+		return nil, err
+	}
+	if operator == "&&" && !Truthy(left) {
+		return Bool(false), nil
+	}
+	if operator == "||" && Truthy(left) {
+		return Bool(true), nil
+	}
+
+	right, err := interp.evaluateExpression(node.Children[1], env)
+	if err != nil {
+		return nil, err
+	}
+	return Bool(Truthy(right)), nil
+}
@@ -0,0 +1,107 @@
+package interpreter
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/AdityaByte/AdiLang/lexer"
+	"github.com/AdityaByte/AdiLang/parser"
+)
+
+func TestIntOverflowBoundaries(t *testing.T) {
+	tests := []struct {
+		left     Int
+		operator string
+		right    Int
+		want     string
+		big      bool
+	}{
+		{math.MaxInt64, "+", 1, "9223372036854775808", true},
+		{math.MaxInt64, "+", 0, "9223372036854775807", false},
+		{math.MinInt64, "+", -1, "-9223372036854775809", true},
+		{math.MinInt64, "-", 1, "-9223372036854775809", true},
+		{math.MaxInt64, "-", -1, "9223372036854775808", true},
+		{math.MinInt64, "-", 0, "-9223372036854775808", false},
+		{math.MinInt64, "/", -1, "9223372036854775808", true},
+		{math.MinInt64, "%", -1, "0", false},
+		{math.MinInt64, "/", 1, "-9223372036854775808", false},
+		{math.MinInt64, "*", -1, "9223372036854775808", true},
+		{-1, "*", math.MinInt64, "9223372036854775808", true},
+		{math.MaxInt64, "*", 2, "18446744073709551614", true},
+		{math.MinInt64, "*", 1, "-9223372036854775808", false},
+		{1 << 32, "*", 1 << 31, "9223372036854775808", true},
+		{-(1 << 32), "*", 1 << 31, "-9223372036854775808", false},
+		{7, "/", -2, "-3", false},
+		{-7, "%", 2, "-1", false},
+	}
+	for _, tt := range tests {
+		got, err := evaluateBinaryOperation(nil, tt.operator, tt.left, tt.right)
+		if err != nil {
+			t.Errorf("%d %s %d: %v", tt.left, tt.operator, tt.right, err)
+			continue
+		}
+		_, isBig := got.(BigInt)
+		if got.String() != tt.want || isBig != tt.big {
+			t.Errorf("%d %s %d = %s (%T), want %s (big %v)", tt.left, tt.operator, tt.right, got, got, tt.want, tt.big)
+		}
+	}
+}
+
+func TestIntLiteralBoundaries(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+		big  bool
+	}{
+		{"-9223372036854775808", "-9223372036854775808", false},
+		{"9223372036854775807", "9223372036854775807", false},
+		{"9223372036854775808", "9223372036854775808", true},
+		{"-9223372036854775809", "-9223372036854775809", true},
+		{"-(-9223372036854775808)", "9223372036854775808", true},
+		{"9223372036854775808 - 1", "9223372036854775807", false},
+	}
+	for _, tt := range tests {
+		p := parser.Parser{Tokens: lexer.Lexer(tt.src)}
+		expr, err := p.ParseExpression()
+		if err != nil {
+			t.Errorf("%s: %v", tt.src, err)
+			continue
+		}
+		got, err := NewInterpreter().Evaluate(expr, NewEnvironment(nil))
+		if err != nil {
+			t.Errorf("%s: %v", tt.src, err)
+			continue
+		}
+		_, isBig := got.(BigInt)
+		if got.String() != tt.want || isBig != tt.big {
+			t.Errorf("%s = %s (%T), want %s (big %v)", tt.src, got, got, tt.want, tt.big)
+		}
+	}
+}
+
+func TestBigIntComparedWithNaN(t *testing.T) {
+	large := Value(BigInt{value: new(big.Int).Lsh(big.NewInt(1), 70)})
+	nan := Value(Float(math.NaN()))
+	tests := []struct {
+		operator    string
+		left, right Value
+		want        Bool
+	}{
+		{"==", large, nan, false},
+		{"==", nan, large, false},
+		{"!=", large, nan, true},
+		{"!=", nan, large, true},
+		{"==", large, Float(math.Inf(1)), false},
+	}
+	for _, tt := range tests {
+		got, err := evaluateBinaryOperation(nil, tt.operator, tt.left, tt.right)
+		if err != nil {
+			t.Errorf("%s %s %s: %v", tt.left, tt.operator, tt.right, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s %s %s = %s, want %s", tt.left, tt.operator, tt.right, got, tt.want)
+		}
+	}
+}
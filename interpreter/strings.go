@@ -0,0 +1,264 @@
+package interpreter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AdityaByte/AdiLang/diagnostics"
+	"github.com/AdityaByte/AdiLang/parser"
+)
+
+// The string builtins count in characters (runes) and not in bytes, like len and the indexes of a string.
+func init() {
+	registerBuiltin("upper", 1, builtinUpper)
+	registerBuiltin("lower", 1, builtinLower)
+	registerBuiltin("trim", 1, builtinTrim)
+	registerBuiltin("split", -1, builtinSplit)
+	registerBuiltin("join", 2, builtinJoin)
+	registerBuiltin("replace", 3, builtinReplace)
+	registerBuiltin("contains", 2, builtinContains)
+	registerBuiltin("startsWith", 2, builtinStartsWith)
+	registerBuiltin("endsWith", 2, builtinEndsWith)
+	registerBuiltin("indexOf", 2, builtinIndexOf)
+	registerBuiltin("substr", -1, builtinSubstr)
+	registerBuiltin("repeat", 2, builtinRepeat)
+	registerBuiltin("format", -1, builtinFormat)
+}
+
+func stringArg(node *parser.ASTNode, name string, args []Value, i int) (string, error) {
+	s, ok := args[i].(String)
+	if !ok {
+		return "", argError(node, i, "%s expects a string, got %s", name, args[i].Type())
+	}
+	return string(s), nil
+}
+
+func intArg(node *parser.ASTNode, name string, args []Value, i int) (int, error) {
+	if _, isBig := args[i].(BigInt); isBig {
+		return 0, tooLargeArg(node, i, name, args[i])
+	}
+	n, ok := args[i].(Int)
+	if !ok {
+		return 0, argError(node, i, "%s expects an int, got %s", name, args[i].Type())
+	}
+	return int(n), nil
+}
+
+// stringArgs checks that all the arguments are strings.
+func stringArgs(node *parser.ASTNode, name string, args []Value) ([]string, error) {
+	result := make([]string, len(args))
+	for i := range args {
+		s, err := stringArg(node, name, args, i)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = s
+	}
+	return result, nil
+}
+
+func builtinUpper(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	s, err := stringArg(node, "upper", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	return String(strings.ToUpper(s)), nil
+}
+
+func builtinLower(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	s, err := stringArg(node, "lower", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	return String(strings.ToLower(s)), nil
+}
+
+// trim(s) removes the spaces, tabs and newlines around the string.
+func builtinTrim(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	s, err := stringArg(node, "trim", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	return String(strings.TrimSpace(s)), nil
+}
+
+// split(s, sep) cuts the string at every sep, split(s) cuts it at the spaces
+// and an empty sep gives the characters.
+func builtinSplit(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, errorAt(node, diagnostics.CodeInvalidOperation, "split expects a string and an optional separator")
+	}
+	strs, err := stringArgs(node, "split", args)
+	if err != nil {
+		return nil, err
+	}
+
+	var parts []string
+	if len(strs) == 1 {
+		parts = strings.Fields(strs[0])
+	} else {
+		parts = strings.Split(strs[0], strs[1])
+	}
+	elements := make([]Value, len(parts))
+	for i, part := range parts {
+		elements[i] = String(part)
+	}
+	return &List{Elements: elements}, nil
+}
+
+// join(xs, sep) puts the elements of the list together with sep between them,
+// the elements which are not strings are written the way out-> prints them.
+func builtinJoin(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	list, err := listArg(node, "join", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	sep, err := stringArg(node, "join", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]string, len(list.Elements))
+	for i, element := range list.Elements {
+		parts[i] = element.String()
+	}
+	return String(strings.Join(parts, sep)), nil
+}
+
+// replace(s, old, new) replaces every old in the string.
+func builtinReplace(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	strs, err := stringArgs(node, "replace", args)
+	if err != nil {
+		return nil, err
+	}
+	return String(strings.ReplaceAll(strs[0], strs[1], strs[2])), nil
+}
+
+func builtinContains(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	strs, err := stringArgs(node, "contains", args)
+	if err != nil {
+		return nil, err
+	}
+	return Bool(strings.Contains(strs[0], strs[1])), nil
+}
+
+func builtinStartsWith(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	strs, err := stringArgs(node, "startsWith", args)
+	if err != nil {
+		return nil, err
+	}
+	return Bool(strings.HasPrefix(strs[0], strs[1])), nil
+}
+
+func builtinEndsWith(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	strs, err := stringArgs(node, "endsWith", args)
+	if err != nil {
+		return nil, err
+	}
+	return Bool(strings.HasSuffix(strs[0], strs[1])), nil
+}
+
+// indexOf(s, sub) gives the character index of the first sub in the string, or -1.
+func builtinIndexOf(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	strs, err := stringArgs(node, "indexOf", args)
+	if err != nil {
+		return nil, err
+	}
+	index := strings.Index(strs[0], strs[1])
+	if index < 0 {
+		return Int(-1), nil
+	}
+	return Int(utf8.RuneCountInString(strs[0][:index])), nil
+}
+
+// substr(s, start) gives the characters from start to the end, substr(s, start, length) at most
+// length characters. A negative start counts from the end and the bounds are clamped like a slice.
+func builtinSubstr(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	if len(args) != 2 && len(args) != 3 {
+		return nil, errorAt(node, diagnostics.CodeInvalidOperation, "substr expects a string, a start and an optional length")
+	}
+	s, err := stringArg(node, "substr", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	start, err := intArg(node, "substr", args, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	chars := []rune(s)
+	from, to := sliceBounds(&start, nil, len(chars))
+	if len(args) == 3 {
+		length, err := intArg(node, "substr", args, 2)
+		if err != nil {
+			return nil, err
+		}
+		if length < 0 {
+			return nil, argError(node, 2, "substr length must not be negative, got %d", length)
+		}
+		to = min(to, from+length)
+	}
+	return String(chars[from:to]), nil
+}
+
+// repeat(s, n) gives the string n times.
+func builtinRepeat(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	s, err := stringArg(node, "repeat", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	count, err := intArg(node, "repeat", args, 1)
+	if err != nil {
+		return nil, err
+	}
+	if count < 0 {
+		return nil, argError(node, 1, "repeat count must not be negative, got %d", count)
+	}
+	// The size is checked before the string is made, it could be too big for the memory.
+	if count > 0 && len(s) > math.MaxInt/count {
+		return nil, argError(node, 1, "repeat count is too big: %d", count)
+	}
+	if err := interp.checkStringSize(node, len(s)*count); err != nil {
+		return nil, err
+	}
+	return String(strings.Repeat(s, count)), nil
+}
+
+// format(f, values...) works like printf, %v shows any value the way out-> prints it,
+// %d, %f, %s, %q, %x and the widths and precisions are the ones of go.
+func builtinFormat(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	if len(args) == 0 {
+		return nil, errorAt(node, diagnostics.CodeInvalidOperation, "format expects a format string")
+	}
+	f, err := stringArg(node, "format", args, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, len(args)-1)
+	for i, arg := range args[1:] {
+		values[i] = goValue(arg)
+	}
+	return String(fmt.Sprintf(f, values...)), nil
+}
+
+// goValue gives the go value fmt knows how to format for the numbers, strings and bools,
+// the other values are formatted through their String method.
+func goValue(value Value) interface{} {
+	switch v := value.(type) {
+	case Int:
+		return int64(v)
+	case BigInt:
+		return v.value
+	case Float:
+		return float64(v)
+	case String:
+		return string(v)
+	case Bool:
+		return bool(v)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,163 @@
+package interpreter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/AdityaByte/AdiLang/diagnostics"
+	"github.com/AdityaByte/AdiLang/parser"
+)
+
+// The fs, env and process modules reach outside of the program, so they only work with
+// the capability granted to the interpreter. The adilang command only grants the ones
+// given with --allow.
+func init() {
+	registerSystemModule("fs", CapFilesystem, map[string]systemFunc{
+		"read":   {1, fsRead},
+		"write":  {2, fsWrite},
+		"exists": {1, fsExists},
+		"list":   {1, fsList},
+	})
+	registerSystemModule("env", CapEnv, map[string]systemFunc{
+		"get": {1, envGet},
+		"set": {2, envSet},
+	})
+	registerSystemModule("process", CapProcess, map[string]systemFunc{
+		"run": {-1, processRun},
+	})
+}
+
+type systemFunc struct {
+	arity int
+	fn    func(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error)
+}
+
+func registerSystemModule(name string, requires Capability, funcs map[string]systemFunc) {
+	members := make(map[string]Value, len(funcs))
+	for member, f := range funcs {
+		members[member] = &Builtin{Name: name + "." + member, Arity: f.arity, Fn: f.fn, Requires: requires}
+	}
+	registerModule(name, members)
+}
+
+// systemError reports an error of the operating system, like a missing file.
+func systemError(node *parser.ASTNode, name string, err error) error {
+	d := errorAt(node, diagnostics.CodeInvalidOperation, "%s: %v", name, err)
+	d.Cause = err
+	return d
+}
+
+// fs.read(path) gives the content of the file as a string.
+func fsRead(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	path, err := stringArg(node, "fs.read", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	if interp.MaxStringSize > 0 {
+		if info, err := os.Stat(path); err == nil {
+			if err := interp.checkStringSize(node, int(info.Size())); err != nil {
+				return nil, err
+			}
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, systemError(node, "fs.read", err)
+	}
+	return String(data), nil
+}
+
+// fs.write(path, text) replaces the content of the file, it is created when it does not exist.
+func fsWrite(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	strs, err := stringArgs(node, "fs.write", args)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(strs[0], []byte(strs[1]), 0o644); err != nil {
+		return nil, systemError(node, "fs.write", err)
+	}
+	return Nil{}, nil
+}
+
+func fsExists(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	path, err := stringArg(node, "fs.exists", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	_, err = os.Stat(path)
+	return Bool(err == nil), nil
+}
+
+// fs.list(dir) gives the sorted names of the files in the directory.
+func fsList(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	dir, err := stringArg(node, "fs.list", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, systemError(node, "fs.list", err)
+	}
+	names := make([]Value, len(entries))
+	for i, entry := range entries {
+		names[i] = String(entry.Name())
+	}
+	return &List{Elements: names}, nil
+}
+
+// env.get(name) gives the environment variable, or nil when it is not set.
+func envGet(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	name, err := stringArg(node, "env.get", args, 0)
+	if err != nil {
+		return nil, err
+	}
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return Nil{}, nil
+	}
+	return String(value), nil
+}
+
+func envSet(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	strs, err := stringArgs(node, "env.set", args)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Setenv(strs[0], strs[1]); err != nil {
+		return nil, systemError(node, "env.set", err)
+	}
+	return Nil{}, nil
+}
+
+// process.run(command, args...) runs the command without a shell and gives what it printed,
+// it is an error when the command fails.
+func processRun(interp *Interpreter, node *parser.ASTNode, args []Value) (Value, error) {
+	if len(args) == 0 {
+		return nil, errorAt(node, diagnostics.CodeInvalidOperation, "process.run expects a command and its arguments")
+	}
+	strs, err := stringArgs(node, "process.run", args)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := interp.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cmd := exec.CommandContext(ctx, strs[0], strs[1:]...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		// The error output of the command tells more than its exit status.
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			err = fmt.Errorf("%s (%w)", message, err)
+		}
+		return nil, systemError(node, "process.run", err)
+	}
+	return String(output), nil
+}
@@ -0,0 +1,169 @@
+package interpreter
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Value is a runtime value of the language. String is how out-> prints the value
+// and Type is the name of its type used in the error messages.
+type Value interface {
+	Type() string
+	String() string
+}
+
+type (
+	Int    int64
+	Float  float64
+	String string
+	Bool   bool
+	// Nil is the value of a function call which did not return anything.
+	Nil struct{}
+)
+
+func (Int) Type() string    { return "int" }
+func (Float) Type() string  { return "float" }
+func (String) Type() string { return "string" }
+func (Bool) Type() string   { return "bool" }
+func (Nil) Type() string    { return "nil" }
+
+func (i Int) String() string { return strconv.FormatInt(int64(i), 10) }
+
+// String always shows a float with a decimal point, so 2.0 is not mistaken for an int.
+func (f Float) String() string {
+	s := strconv.FormatFloat(float64(f), 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eEnN") {
+		s += ".0"
+	}
+	return s
+}
+
+func (s String) String() string { return string(s) }
+func (b Bool) String() string   { return strconv.FormatBool(bool(b)) }
+func (Nil) String() string      { return "nil" }
+
+// Inspect formats a value the way it is shown inside a list or map, strings are quoted there.
+func Inspect(value Value) string {
+	return inspect(value, nil)
+}
+
+// inspect keeps the lists and maps which are being formatted in seen, a list or map can
+// contain itself and is then shown as [...] or {...} instead of going on forever.
+func inspect(value Value, seen map[Value]bool) string {
+	switch v := value.(type) {
+	case String:
+		return strconv.Quote(string(v))
+	case *List:
+		return v.format(seen)
+	case *Map:
+		return v.format(seen)
+	}
+	return value.String()
+}
+
+// Truthy tells if the value counts as true in a condition. false, nil, zero,
+// the empty string and the empty list or map are false, everything else is true.
+func Truthy(value Value) bool {
+	switch v := value.(type) {
+	case Bool:
+		return bool(v)
+	case Nil:
+		return false
+	case Int:
+		return v != 0
+	case Float:
+		return v != 0
+	case String:
+		return v != ""
+	case *List:
+		return len(v.Elements) > 0
+	case *Map:
+		return v.Len() > 0
+	default:
+		return true
+	}
+}
+
+// Equal compares two values by their content, lists and maps are equal when all their
+// elements are. Values of different types are never equal except an int and a float
+// with the same value, and functions are only equal to themselves.
+func Equal(a, b Value) bool {
+	return equal(a, b, nil)
+}
+
+// comparison is a pair of lists or maps being compared.
+type comparison struct {
+	a, b Value
+}
+
+// equal keeps the pairs of lists and maps already being compared in seen, when the same
+// pair comes up again, through a list containing itself, it is taken as equal.
+func equal(a, b Value, seen map[comparison]bool) bool {
+	switch x := a.(type) {
+	case *List:
+		y, ok := b.(*List)
+		if !ok || len(x.Elements) != len(y.Elements) {
+			return false
+		}
+		if seen[comparison{x, y}] {
+			return true
+		}
+		if seen == nil {
+			seen = map[comparison]bool{}
+		}
+		seen[comparison{x, y}] = true
+		for i := range x.Elements {
+			if !equal(x.Elements[i], y.Elements[i], seen) {
+				return false
+			}
+		}
+		return true
+	case *Map:
+		y, ok := b.(*Map)
+		if !ok || x.Len() != y.Len() {
+			return false
+		}
+		if seen[comparison{x, y}] {
+			return true
+		}
+		if seen == nil {
+			seen = map[comparison]bool{}
+		}
+		seen[comparison{x, y}] = true
+		for _, key := range x.keys {
+			value, _ := x.Get(key)
+			other, ok := y.Get(key)
+			if !ok || !equal(value, other, seen) {
+				return false
+			}
+		}
+		return true
+	case Int:
+		// 1 == 1.0 like in the arithmetic an int is compared with a float as a float.
+		if y, ok := b.(Float); ok {
+			return Float(x) == y
+		}
+	case BigInt:
+		switch y := b.(type) {
+		case BigInt:
+			return x.value.Cmp(y.value) == 0
+		case Float:
+			// NaN is not equal to anything and big.NewFloat panics on it.
+			if math.IsNaN(float64(y)) {
+				return false
+			}
+			return new(big.Float).SetInt(x.value).Cmp(big.NewFloat(float64(y))) == 0
+		}
+		return false
+	case Float:
+		switch y := b.(type) {
+		case Int:
+			return x == Float(y)
+		case BigInt:
+			return Equal(y, x)
+		}
+	}
+	return a == b
+}
@@ -1,20 +1,291 @@
 package lexer
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
+// scanner keeps the source as runes together with the position of every rune,
+// so the tokens can be stamped with where they came from.
+type scanner struct {
+	file   string
+	chars  []rune
+	lines  []int
+	cols   []int
+	offset []int
+	tokens []Token
+
+	// keywordParens has an entry for every open '(', true when it belongs to var( or range(.
+	// closedKeywordParen tells if the last ')' closed one of them.
+	keywordParens      []bool
+	closedKeywordParen bool
+}
+
+func newScanner(file, input string) *scanner {
+	s := &scanner{file: file, chars: []rune(input)}
+
+	// One extra slot so the position just after the last character is known too.
+	n := len(s.chars) + 1
+	s.lines = make([]int, n)
+	s.cols = make([]int, n)
+	s.offset = make([]int, n)
+
+	line, col, offset := 1, 1, 0
+	for i, char := range s.chars {
+		s.lines[i], s.cols[i], s.offset[i] = line, col, offset
+		offset += utf8.RuneLen(char)
+		if char == '\n' {
+			line++
+			col = 1
+		} else {
+			col++
+		}
+	}
+	s.lines[n-1], s.cols[n-1], s.offset[n-1] = line, col, offset
+	return s
+}
+
+func (s *scanner) position(i int) Position {
+	return Position{File: s.file, Line: s.lines[i], Column: s.cols[i], Offset: s.offset[i]}
+}
+
+// emit appends a token which spans the characters from start up to (not including) end.
+func (s *scanner) emit(tokenType TokenType, value string, start, end int) {
+	s.tokens = append(s.tokens, Token{
+		Type:  tokenType,
+		Value: value,
+		Pos:   s.position(start),
+		End:   s.position(end),
+	})
+}
+
+// emitError appends an IllegalToken for the characters from start to end which could not be read,
+// the parser reports the message when it reaches the token.
+func (s *scanner) emitError(message string, start, end int) {
+	s.emit(IllegalToken, string(s.chars[start:end]), start, end)
+	s.tokens[len(s.tokens)-1].Error = message
+}
+
+// scanString reads the "..." string starting at i and returns where it ends. The escapes are
+// replaced by the characters they stand for, a string can not go past the end of its line,
+// backtick strings are used for that.
+//
+// A string with ${...} in it is split into a StringStart, StringMiddle and StringEnd token for
+// the text around the interpolations, with the tokens of the expressions in between.
+func (s *scanner) scanString(start int) int {
+	chars := s.chars
+	var value strings.Builder
+	partStart, interpolated := start, false
+	i := start + 1
+	for i < len(chars) && chars[i] != '"' && chars[i] != '\n' {
+		if chars[i] == '$' && i+1 < len(chars) && chars[i+1] == '{' {
+			partType := StringMiddle
+			if !interpolated {
+				partType = StringStart
+			}
+			s.emit(partType, value.String(), partStart, i+2)
+			value.Reset()
+			interpolated = true
+
+			open := i
+			i = s.scanTokens(i+2, true)
+			if i >= len(chars) || chars[i] != '}' {
+				s.emitError("expected '}' to close the interpolation", open, open+2)
+				return s.skipString(i)
+			}
+			partStart = i
+			i++
+			continue
+		}
+
+		if chars[i] != '\\' {
+			value.WriteRune(chars[i])
+			i++
+			continue
+		}
+
+		char, next, err := s.scanEscape(i)
+		if err != "" {
+			s.emitError(err, i, next)
+			return s.skipString(next)
+		}
+		value.WriteRune(char)
+		i = next
+	}
+
+	if i >= len(chars) || chars[i] != '"' {
+		s.emitError("unterminated string literal", start, start+1)
+		return i
+	}
+	partType := StringLiteral
+	if interpolated {
+		partType = StringEnd
+	}
+	s.emit(partType, value.String(), partStart, i+1)
+	return i + 1
+}
+
+// scanEscape reads the escape sequence starting with the backslash at i, it returns the
+// character, where the escape ends and an error message when it is not valid.
+func (s *scanner) scanEscape(i int) (rune, int, string) {
+	chars := s.chars
+	if i+1 >= len(chars) || chars[i+1] == '\n' {
+		return 0, i + 1, "unterminated string literal"
+	}
+
+	switch chars[i+1] {
+	case 'n':
+		return '\n', i + 2, ""
+	case 't':
+		return '\t', i + 2, ""
+	case 'r':
+		return '\r', i + 2, ""
+	case '"':
+		return '"', i + 2, ""
+	case '\\':
+		return '\\', i + 2, ""
+	case '$':
+		return '$', i + 2, ""
+	case 'u':
+		// \u{1F600}, from 1 to 6 hex digits between the braces.
+		j := i + 2
+		if j >= len(chars) || chars[j] != '{' {
+			return 0, j, "expected '{' after \\u, like \\u{1F600}"
+		}
+		j++
+		digits := j
+		for j < len(chars) && j-digits < 6 && isHexDigit(chars[j]) {
+			j++
+		}
+		if j == digits || j >= len(chars) || chars[j] != '}' {
+			return 0, j, "invalid unicode escape, expected 1 to 6 hex digits between the braces like \\u{1F600}"
+		}
+		code, _ := strconv.ParseInt(string(chars[digits:j]), 16, 32)
+		if !utf8.ValidRune(rune(code)) {
+			return 0, j + 1, fmt.Sprintf("invalid unicode code point: %s", string(chars[digits:j]))
+		}
+		return rune(code), j + 1, ""
+	default:
+		return 0, i + 2, fmt.Sprintf("unknown escape sequence: \\%c", chars[i+1])
+	}
+}
+
+// skipString skips the rest of a string after an error in it, so the lexer goes on after the string.
+func (s *scanner) skipString(i int) int {
+	chars := s.chars
+	for i < len(chars) && chars[i] != '"' && chars[i] != '\n' {
+		if chars[i] == '\\' && i+1 < len(chars) && chars[i+1] != '\n' {
+			i++
+		}
+		i++
+	}
+	if i < len(chars) && chars[i] == '"' {
+		i++
+	}
+	return i
+}
+
+// scanRawString reads a `...` string, it is taken as it is without escapes and can span lines.
+func (s *scanner) scanRawString(start int) int {
+	chars := s.chars
+	i := start + 1
+	for i < len(chars) && chars[i] != '`' {
+		i++
+	}
+	if i >= len(chars) {
+		s.emitError(errUnterminatedRawString, start, start+1)
+		return i
+	}
+	s.emit(StringLiteral, string(chars[start+1:i]), start, i+1)
+	return i + 1
+}
+
+func isHexDigit(char rune) bool {
+	return isDigit(char) || (char >= 'a' && char <= 'f') || (char >= 'A' && char <= 'F')
+}
+
+// afterOperand tells if the character at i directly follows a value on the same line,
+// like a number, identifier, string, bool or closing parenthesis. A '%' in that place is the
+// modulo operator, anywhere else it opens a multi-line comment. The ')' of var(...) and
+// range(...) ends a statement and not a value, so var(x = 10) % note % is still a comment.
+func (s *scanner) afterOperand(i int) bool {
+	if len(s.tokens) == 0 {
+		return false
+	}
+	last := s.tokens[len(s.tokens)-1]
+	if last.End.Line != s.lines[i] {
+		return false
+	}
+	switch last.Type {
+	case Identifier, NumberLiteral, StringLiteral, StringEnd, BooleanLiteral, RBracket:
+		return true
+	case RParen:
+		return !s.closedKeywordParen
+	}
+	return false
+}
+
+// Lexer converts the source code into tokens, the positions have no file name.
 func Lexer(input string) []Token {
-	var tokens []Token
-	var currentToken strings.Builder
-	chars := []rune(input)
-	i := 0
+	return LexFile("", input)
+}
+
+// LexFile converts the source code into tokens and records the filename in their positions.
+func LexFile(filename, input string) []Token {
+	s := newScanner(filename, input)
+	s.scanTokens(0, false)
+	return s.tokens
+}
+
+const errUnterminatedRawString = "unterminated raw string literal"
+
+// ErrUnterminatedComment is the Error of the IllegalToken for a % comment without its closing %.
+const ErrUnterminatedComment = "unterminated comment"
+
+// IsIncomplete tells if the input stops inside a block, parenthesis, brackets, a raw string or a comment,
+// the REPL asks for more lines until it is complete.
+func IsIncomplete(input string) bool {
+	depth := 0
+	for _, token := range Lexer(input) {
+		switch token.Type {
+		case LBrace, LParen, LBracket:
+			depth++
+		case RBrace, RParen, RBracket:
+			depth--
+		case IllegalToken:
+			if token.Error == errUnterminatedRawString || token.Error == ErrUnterminatedComment {
+				return true
+			}
+		}
+	}
+	return depth > 0
+}
+
+// scanTokens lexes the characters from i on. For the ${...} of a string interpolation it stops
+// at the '}' closing the interpolation, or at the end of the line, and returns where it stopped.
+func (s *scanner) scanTokens(i int, interpolation bool) int {
+	chars := s.chars
 	length := len(chars)
+	depth := 0 // braces opened inside the interpolation
 
 	for i < length {
 		char := chars[i]
 
+		if interpolation {
+			if char == '\n' || (char == '}' && depth == 0) {
+				return i
+			}
+			switch char {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			}
+		}
+
 		// Skipping the spaces.
 		if unicode.IsSpace(char) {
 			i++
@@ -27,99 +298,157 @@ func Lexer(input string) []Token {
 				i++
 			}
 			continue
-		} else if char == '%' {
+		} else if char == '%' && !s.afterOperand(i) {
+			start := i
 			i++
 			for i < length && chars[i] != '%' {
 				i++
 			}
+			if i >= length {
+				s.emitError(ErrUnterminatedComment, start, start+1)
+			}
 			i++ // Skipping the last %
 			continue
 		}
 
 		// Handling strings.
 		if char == '"' {
-			currentToken.Reset()
-			i++
-			for i < length && chars[i] != '"' {
-				currentToken.WriteRune(chars[i])
-				i++
-			}
-			tokens = append(tokens, Token{StringLiteral, currentToken.String()})
-			// Resetting the current token
-			currentToken.Reset()
-			i++ // skipping closing "
+			i = s.scanString(i)
+			continue
+		}
+		if char == '`' {
+			i = s.scanRawString(i)
 			continue
 		}
 
 		// Handling multicharacters
 		if char == '-' && i+1 < length && chars[i+1] == '>' {
-			tokens = append(tokens, Token{PrintOperator, "->"})
+			s.emit(PrintOperator, "->", i, i+2)
 			i += 2
 			continue
 		}
 
 		// Handling multicharacters -> ==
-		if char == '=' && i+1 < length && chars[i+1] == '='{
-			tokens = append(tokens, Token{ComparisionOperator, "=="})
+		if char == '=' && i+1 < length && chars[i+1] == '=' {
+			s.emit(ComparisionOperator, "==", i, i+2)
 			i += 2
 			continue
 		}
 
 		// Handling multicharacters -> !=
 		if char == '!' && i+1 < length && chars[i+1] == '=' {
-			tokens = append(tokens, Token{NotEqualsOperator, "!="})
+			s.emit(NotEqualsOperator, "!=", i, i+2)
 			i += 2
 			continue
 		}
 
-		// Handling single character tokens
-		if isDelimiter(char) {
-			if currentToken.Len() > 0 {
-				tokens = append(tokens, classifyToken(currentToken.String()))
-				currentToken.Reset()
+		// Handling multicharacters -> >= <= && || and the compound assignments
+		if i+1 < length {
+			switch string(chars[i : i+2]) {
+			case ">=":
+				s.emit(GreaterEqualOperator, ">=", i, i+2)
+				i += 2
+				continue
+			case "<=":
+				s.emit(LessEqualOperator, "<=", i, i+2)
+				i += 2
+				continue
+			case "&&":
+				s.emit(AndOperator, "&&", i, i+2)
+				i += 2
+				continue
+			case "||":
+				s.emit(OrOperator, "||", i, i+2)
+				i += 2
+				continue
+			case "+=", "-=", "*=", "/=", "%=":
+				s.emit(CompoundAssignOperator, string(chars[i:i+2]), i, i+2)
+				i += 2
+				continue
 			}
+		}
 
+		// Handling single character tokens
+		if isDelimiter(char) {
 			switch char {
 			case '=':
-				tokens = append(tokens, Token{AssignOperator, "="})
+				s.emit(AssignOperator, "=", i, i+1)
 			case '(':
-				tokens = append(tokens, Token{LParen, "("})
+				afterKeyword := len(s.tokens) > 0 && (s.tokens[len(s.tokens)-1].Type == VarKeyword || s.tokens[len(s.tokens)-1].Type == RangeKeyword)
+				s.keywordParens = append(s.keywordParens, afterKeyword)
+				s.emit(LParen, "(", i, i+1)
 			case ')':
-				tokens = append(tokens, Token{RParen, ")"})
+				s.closedKeywordParen = false
+				if n := len(s.keywordParens); n > 0 {
+					s.closedKeywordParen = s.keywordParens[n-1]
+					s.keywordParens = s.keywordParens[:n-1]
+				}
+				s.emit(RParen, ")", i, i+1)
+			case ',':
+				s.emit(Comma, ",", i, i+1)
+			case '[':
+				s.emit(LBracket, "[", i, i+1)
+			case ']':
+				s.emit(RBracket, "]", i, i+1)
+			case ':':
+				s.emit(Colon, ":", i, i+1)
+			case '.':
+				s.emit(Dot, ".", i, i+1)
 			case '{':
-				tokens = append(tokens, Token{LBrace, "{"})
+				s.emit(LBrace, "{", i, i+1)
 			case '}':
-				tokens = append(tokens, Token{RBrace, "}"})
+				s.emit(RBrace, "}", i, i+1)
 			case '>':
-				tokens = append(tokens, Token{GreaterThanOperator, ">"})
+				s.emit(GreaterThanOperator, ">", i, i+1)
 			case '<':
-				tokens = append(tokens, Token{LessThanOperator, "<"})
+				s.emit(LessThanOperator, "<", i, i+1)
 			case '+':
-				tokens = append(tokens, Token{PlusOperator, "+"})
+				s.emit(PlusOperator, "+", i, i+1)
+			case '-':
+				s.emit(MinusOperator, "-", i, i+1)
+			case '*':
+				s.emit(MultiplyOperator, "*", i, i+1)
+			case '/':
+				s.emit(DivideOperator, "/", i, i+1)
+			case '%':
+				s.emit(ModuloOperator, "%", i, i+1)
+			case '!':
+				s.emit(NotOperator, "!", i, i+1)
+			case '&', '|':
+				// A single & or | is not an operator of the language.
+				s.emit(IllegalToken, string(char), i, i+1)
 			}
 			i++
 			continue
 		}
 
-		// Handling identifiers/keywords/numbers
-		currentToken.WriteRune(char)
-		i++
-
-		// checking the next character is the part of the same token
-		if i < length && !isDelimiterOrSpace(chars[i]) {
+		// Handling numbers, they are scanned on their own because the exponent
+		// of a float like 1e-9 has a '-' which is a delimiter everywhere else.
+		if isDigit(char) {
+			start := i
+			i = s.scanNumber(i)
+			s.emit(NumberLiteral, string(chars[start:i]), start, i)
 			continue
 		}
 
-		// classify and reset the current token
-		tokens = append(tokens, classifyToken(currentToken.String()))
-		currentToken.Reset()
+		// Handling identifiers/keywords
+		// the token goes on until the next delimiter or space.
+		start := i
+		i++
+		for i < length && !isDelimiterOrSpace(chars[i]) {
+			i++
+		}
+
+		// classify the token
+		token := classifyToken(string(chars[start:i]))
+		s.emit(token.Type, token.Value, start, i)
 	}
-	return tokens
+	return i
 }
 
 func isDelimiter(char rune) bool {
 	switch char {
-	case '=', '(', ')', '{', '}', '-', '>', '<', '+': // Added < in this
+	case '=', '(', ')', '{', '}', '-', '>', '<', '+', '*', '/', '%', '!', '&', '|', ',', '[', ']', ':', '.': // Added < in this
 		return true
 	default:
 		return false
@@ -130,36 +459,95 @@ func isDelimiterOrSpace(char rune) bool {
 	return unicode.IsSpace(char) || isDelimiter(char)
 }
 
+var keywords = map[string]TokenType{
+	"var":       VarKeyword,
+	"out":       OutKeyword,
+	"ifdude":    IfKeyword,
+	"else":      ElseKeyword,
+	"fordude":   ForDudeKeyword,
+	"in":        InKeyword,
+	"range":     RangeKeyword,
+	"fundude":   FunDudeKeyword,
+	"return":    ReturnKeyword,
+	"whiledude": WhileDudeKeyword,
+	"break":     BreakKeyword,
+	"continue":  ContinueKeyword,
+	"true":      BooleanLiteral,
+	"false":     BooleanLiteral,
+}
+
 func classifyToken(input string) Token {
-	switch input {
-	case "var":
-		return Token{VarKeyword, input}
-	case "out":
-		return Token{OutKeyword, input}
-	case "ifdude":
-		return Token{IfKeyword, input}
-	case "else":
-		return Token{ElseKeyword, input}
-	case "fordude":
-		return Token{ForDudeKeyword, input}
-	case "in":
-		return Token{InKeyword, input}
-	case "range":
-		return Token{RangeKeyword, input}
+	if tokenType, ok := keywords[input]; ok {
+		return Token{Type: tokenType, Value: input}
 	}
 
-	if isNumber(input) {
-		return Token{NumberLiteral, input}
+	return Token{Type: Identifier, Value: input}
+}
+
+// SuggestKeyword returns the keyword which looks like a misspelling of the identifier,
+// e.g. "fordud" gives "fordude". It reports false when no keyword is close enough.
+func SuggestKeyword(ident string) (string, bool) {
+	best, bestDistance := "", 0
+	for keyword := range keywords {
+		// Short keywords like "in" are close to almost everything, so they are never suggested.
+		if len(keyword) < 3 || keyword == ident {
+			continue
+		}
+		distance := editDistance(ident, keyword)
+		if distance > 2 || distance >= len(keyword)/2+1 {
+			continue
+		}
+		if best == "" || distance < bestDistance || (distance == bestDistance && keyword < best) {
+			best, bestDistance = keyword, distance
+		}
 	}
+	return best, best != ""
+}
 
-	return Token{Identifier, input}
+// editDistance is the levenshtein distance between the two strings.
+func editDistance(a, b string) int {
+	x, y := []rune(a), []rune(b)
+	prev := make([]int, len(y)+1)
+	curr := make([]int, len(y)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(x); i++ {
+		curr[0] = i
+		for j := 1; j <= len(y); j++ {
+			cost := 1
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(y)]
 }
 
-func isNumber(s string) bool {
-	for _, c := range s {
-		if !unicode.IsDigit(c) {
-			return false
+// scanNumber returns where the number starting at i ends. Letters and '_' are taken
+// as part of the number for the 0x/0o/0b prefixes, hex digits and separators,
+// the parser reports the ones which do not make a valid number.
+func (s *scanner) scanNumber(i int) int {
+	chars := s.chars
+	hex := chars[i] == '0' && i+1 < len(chars) && (chars[i+1] == 'x' || chars[i+1] == 'X')
+	for i < len(chars) {
+		char := chars[i]
+		switch {
+		case !hex && (char == 'e' || char == 'E') && i+1 < len(chars) && (chars[i+1] == '+' || chars[i+1] == '-'):
+			i += 2
+		case isDigit(char) || unicode.IsLetter(char) || char == '_':
+			i++
+		case char == '.' && i+1 < len(chars) && isDigit(chars[i+1]):
+			i++
+		default:
+			return i
 		}
 	}
-	return true
+	return i
+}
+
+func isDigit(char rune) bool {
+	return char >= '0' && char <= '9'
 }
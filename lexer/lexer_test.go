@@ -0,0 +1,77 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+)
+
+// values joins the values of the tokens, an illegal token is shown with its error.
+func values(tokens []Token) string {
+	parts := make([]string, len(tokens))
+	for i, token := range tokens {
+		parts[i] = token.Value
+		if token.Type == IllegalToken {
+			parts[i] = "<" + token.Error + ">"
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestPercentModuloOrComment(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"after an identifier", "x % 2", "x % 2"},
+		{"after a number", "7 % 3", "7 % 3"},
+		{"after a string", `out->"hi" % 2`, "out -> hi % 2"},
+		{"after a bool", "true % 2", "true % 2"},
+		{"after a parenthesis", "(a + b) % 3", "( a + b ) % 3"},
+		{"after an index", "xs[0] % 2", "xs [ 0 ] % 2"},
+		{"without spaces", "x%2", "x % 2"},
+		{"after var(...)", "var(x = 10) % note %\nout->x", "var ( x = 10 ) out -> x"},
+		{"after range(...)", "fordude i in range(3) % note % { }", "fordude i in range ( 3 ) { }"},
+		{"after a call", "f(x) % 2", "f ( x ) % 2"},
+		{"after an operator", "x = % note % 5", "x = 5"},
+		{"at the start of a line", "x\n% note %\ny", "x y"},
+		{"over multiple lines", "% first\nsecond %\nx", "x"},
+		{"at the start of the file", "% note % out->1", "out -> 1"},
+		{"unterminated", "out->1\n% note\nout->2", "out -> 1 <" + ErrUnterminatedComment + ">"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := values(Lexer(tt.src)); got != tt.want {
+				t.Errorf("Lexer(%q) = %q, want %q", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnterminatedCommentPosition(t *testing.T) {
+	tokens := Lexer("out->1\n  % note")
+	last := tokens[len(tokens)-1]
+	if last.Type != IllegalToken || last.Error != ErrUnterminatedComment {
+		t.Fatalf("last token = %+v, want the unterminated comment", last)
+	}
+	if last.Pos.Line != 2 || last.Pos.Column != 3 {
+		t.Errorf("position = %v, want 2:3", last.Pos)
+	}
+}
+
+func TestIsIncompleteComment(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{"% note", true},
+		{"% note %", false},
+		{"x % 2", false},
+		{"var(x = 1) % note", true},
+	}
+	for _, tt := range tests {
+		if got := IsIncomplete(tt.src); got != tt.want {
+			t.Errorf("IsIncomplete(%q) = %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
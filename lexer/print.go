@@ -0,0 +1,14 @@
+package lexer
+
+import (
+	"fmt"
+	"io"
+)
+
+// Fprint writes the tokens one per line with their line:column, type and value.
+func Fprint(w io.Writer, tokens []Token) {
+	for _, token := range tokens {
+		position := fmt.Sprintf("%d:%d", token.Pos.Line, token.Pos.Column)
+		fmt.Fprintf(w, "%-8s %-20s %q\n", position, token.Type, token.Value)
+	}
+}
@@ -1,45 +1,95 @@
 package lexer
 
+import "fmt"
+
 type TokenType string
 
 const (
-	// Identifiers and literals 
-	Identifier TokenType = "IDENTIFIER"
-	NumberLiteral TokenType = "NUMBER"
-	StringLiteral TokenType = "STRING"
+	// Identifiers and literals
+	Identifier     TokenType = "IDENTIFIER"
+	NumberLiteral  TokenType = "NUMBER"
+	StringLiteral  TokenType = "STRING"
+	StringStart    TokenType = "STRING_START"  // "text${ of an interpolated string
+	StringMiddle   TokenType = "STRING_MIDDLE" // }text${
+	StringEnd      TokenType = "STRING_END"    // }text"
+	BooleanLiteral TokenType = "BOOLEAN"       // true, false
 
 	// Keywords :
-	VarKeyword TokenType = "VARIABLE"
-	OutKeyword TokenType = "OUTPUT"
-	IfKeyword TokenType = "IF"
-	ElseKeyword TokenType = "ELSE"
-	ForDudeKeyword TokenType = "FOR_DUDE" // For for loop
-	InKeyword TokenType = "IN"
-	RangeKeyword TokenType = "RANGE"
+	VarKeyword       TokenType = "VARIABLE"
+	OutKeyword       TokenType = "OUTPUT"
+	IfKeyword        TokenType = "IF"
+	ElseKeyword      TokenType = "ELSE"
+	ForDudeKeyword   TokenType = "FOR_DUDE" // For for loop
+	InKeyword        TokenType = "IN"
+	RangeKeyword     TokenType = "RANGE"
+	FunDudeKeyword   TokenType = "FUN_DUDE" // For function declaration
+	ReturnKeyword    TokenType = "RETURN"
+	WhileDudeKeyword TokenType = "WHILE_DUDE" // For while loop
+	BreakKeyword     TokenType = "BREAK"
+	ContinueKeyword  TokenType = "CONTINUE"
 
 	// Operators
-	AssignOperator TokenType = "ASSIGN"
-	PlusOperator TokenType = "PLUS"
-	MinusOperator TokenType = "MINUS"
-	PrintOperator TokenType = "PRINTOPERATOR" // ->
-	GreaterThanOperator TokenType = "GREATERTHAN" // >
-	LessThanOperator TokenType = "LESSTHAN" // <
-	ComparisionOperator TokenType = "COMPARISION" // ==
-	NotEqualsOperator TokenType = "NOTEQUALS" // !=
+	AssignOperator         TokenType = "ASSIGN"
+	CompoundAssignOperator TokenType = "COMPOUND_ASSIGN" // += -= *= /= %=
+	PlusOperator           TokenType = "PLUS"
+	MinusOperator          TokenType = "MINUS"
+	MultiplyOperator       TokenType = "MULTIPLY"      // *
+	DivideOperator         TokenType = "DIVIDE"        // /
+	ModuloOperator         TokenType = "MODULO"        // %
+	PrintOperator          TokenType = "PRINTOPERATOR" // ->
+	GreaterThanOperator    TokenType = "GREATERTHAN"   // >
+	LessThanOperator       TokenType = "LESSTHAN"      // <
+	ComparisionOperator    TokenType = "COMPARISION"   // ==
+	NotEqualsOperator      TokenType = "NOTEQUALS"     // !=
+	GreaterEqualOperator   TokenType = "GREATEREQUAL"  // >=
+	LessEqualOperator      TokenType = "LESSEQUAL"     // <=
+	AndOperator            TokenType = "AND"           // &&
+	OrOperator             TokenType = "OR"            // ||
+	NotOperator            TokenType = "NOT"           // !
 
 	// Brackets
-	LBrace TokenType = "LEFTBRACE"
-	RBrace TokenType = "RIGHTBRACE"
-	LParen TokenType = "LEFTPARENTHESIS"
-	RParen TokenType = "RIGHTPARENTHESIS"
+	LBrace   TokenType = "LEFTBRACE"
+	RBrace   TokenType = "RIGHTBRACE"
+	LParen   TokenType = "LEFTPARENTHESIS"
+	RParen   TokenType = "RIGHTPARENTHESIS"
+	LBracket TokenType = "LEFTBRACKET"
+	RBracket TokenType = "RIGHTBRACKET"
+
+	// Separators
+	Comma TokenType = "COMMA"
+	Colon TokenType = "COLON"
+	Dot   TokenType = "DOT"
 
 	// Special Case
 	IllegalToken TokenType = "ILLEGAL"
 )
 
+// Position is a location in the source code.
+// Line and Column start from 1, the column is counted in characters (runes)
+// and Offset is the byte offset from the beginning of the file.
+type Position struct {
+	File   string `json:"file,omitempty"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+	Offset int    `json:"offset"`
+}
+
+// String formats the position as file:line:col, the file is left out when it is not known.
+func (p Position) String() string {
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
 // Structure of the Token
+// Pos is where the token starts and End is the position just after its last character.
+// Error is only set on an IllegalToken the lexer could not read, like an unterminated string.
 
 type Token struct {
-	Type TokenType
+	Type  TokenType
 	Value string
-}
\ No newline at end of file
+	Pos   Position
+	End   Position
+	Error string
+}
@@ -0,0 +1,113 @@
+package adilang
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/AdityaByte/AdiLang/interpreter"
+)
+
+// NativeFunc is a go function the programs can call. It checks its arguments itself,
+// an *ArgumentError makes the error point at the wrong argument.
+type NativeFunc func(args []Value) (Value, error)
+
+// ArgumentError is the error of a NativeFunc for a wrong argument.
+type ArgumentError = interpreter.ArgumentError
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// Register makes fn callable under the name, as a global so a variable of the program
+// with the same name hides it.
+func (vm *VM) Register(name string, fn NativeFunc) {
+	vm.globals.Set(name, interpreter.NewNative(name, -1, fn))
+}
+
+// RegisterFunc makes an ordinary go function like func(string, int) (string, error) callable
+// under the name. The arguments are converted from the values of the language and checked
+// before the call, see WrapFunc.
+func (vm *VM) RegisterFunc(name string, fn interface{}) error {
+	native, arity, err := wrapFunc(name, fn)
+	if err != nil {
+		return err
+	}
+	vm.globals.Set(name, interpreter.NewNative(name, arity, native))
+	return nil
+}
+
+// WrapFunc turns a go function into a NativeFunc. The parameters can be bools, numbers,
+// strings, *big.Int, slices, maps, Values or interface{} (which gets the ToGo value), variadic
+// functions take any number of arguments at the end. The function gives nothing, a value,
+// an error or a value and an error, the value is converted with FromGo.
+func WrapFunc(name string, fn interface{}) (NativeFunc, error) {
+	native, arity, err := wrapFunc(name, fn)
+	if err != nil {
+		return nil, err
+	}
+	if arity < 0 {
+		return native, nil
+	}
+	// The arity is checked by the interpreter for the registered functions, a NativeFunc checks it itself.
+	return func(args []Value) (Value, error) {
+		if len(args) != arity {
+			return nil, fmt.Errorf("expects %d arguments, got %d", arity, len(args))
+		}
+		return native(args)
+	}, nil
+}
+
+// wrapFunc gives the NativeFunc and the number of arguments of fn, -1 when it is variadic.
+func wrapFunc(name string, fn interface{}) (NativeFunc, int, error) {
+	f := reflect.ValueOf(fn)
+	if f.Kind() != reflect.Func || f.IsNil() {
+		return nil, 0, fmt.Errorf("%s: expected a function, got %T", name, fn)
+	}
+	t := f.Type()
+
+	switch {
+	case t.NumOut() > 2,
+		t.NumOut() == 2 && t.Out(1) != errorType:
+		return nil, 0, fmt.Errorf("%s: a function must give at most a value and an error, %s does not", name, t)
+	}
+	returnsError := t.NumOut() > 0 && t.Out(t.NumOut()-1) == errorType
+
+	params := t.NumIn()
+	arity := params
+	if t.IsVariadic() {
+		params--
+		arity = -1
+	}
+
+	native := func(args []Value) (Value, error) {
+		if len(args) < params {
+			return nil, fmt.Errorf("expects at least %d arguments, got %d", params, len(args))
+		}
+
+		in := make([]reflect.Value, len(args))
+		for i, arg := range args {
+			var paramType reflect.Type
+			if i < params {
+				paramType = t.In(i)
+			} else {
+				paramType = t.In(params).Elem()
+			}
+			v, err := toReflect(arg, paramType)
+			if err != nil {
+				return nil, &ArgumentError{Index: i, Err: fmt.Errorf("%s: %w", name, err)}
+			}
+			in[i] = v
+		}
+
+		out := f.Call(in)
+		if returnsError {
+			if err, _ := out[len(out)-1].Interface().(error); err != nil {
+				return nil, err
+			}
+			out = out[:len(out)-1]
+		}
+		if len(out) == 0 {
+			return Nil{}, nil
+		}
+		return fromReflect(out[0])
+	}
+	return native, arity, nil
+}
@@ -0,0 +1,222 @@
+package adilang
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/AdityaByte/AdiLang/interpreter"
+)
+
+// bigValue gives the int value of the decimal number.
+func bigValue(text string) Value {
+	n, _ := new(big.Int).SetString(text, 10)
+	return interpreter.NewBigInt(n)
+}
+
+func TestWrapFunc(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      interface{}
+		args    []Value
+		want    Value
+		wantErr string
+		index   int // of the argument in the *ArgumentError, -1 when the error is no ArgumentError
+	}{
+		{
+			name: "ints",
+			fn:   func(a, b int) int { return a + b },
+			args: []Value{Int(1), Int(2)},
+			want: Int(3),
+		},
+		{
+			name:    "too few arguments",
+			fn:      func(a, b int) int { return a + b },
+			args:    []Value{Int(1)},
+			wantErr: "expects 2 arguments, got 1",
+			index:   -1,
+		},
+		{
+			name:    "too many arguments",
+			fn:      func(a, b int) int { return a + b },
+			args:    []Value{Int(1), Int(2), Int(3)},
+			wantErr: "expects 2 arguments, got 3",
+			index:   -1,
+		},
+		{
+			name:    "wrong type",
+			fn:      func(a, b int) int { return a + b },
+			args:    []Value{Int(1), String("2")},
+			wantErr: "argument 2: f: expected int, got string",
+			index:   1,
+		},
+		{
+			name: "variadic",
+			fn:   func(sep string, parts ...string) string { return strings.Join(parts, sep) },
+			args: []Value{String("-"), String("a"), String("b")},
+			want: String("a-b"),
+		},
+		{
+			name: "variadic without the rest",
+			fn:   func(sep string, parts ...string) string { return strings.Join(parts, sep) },
+			args: []Value{String("-")},
+			want: String(""),
+		},
+		{
+			name:    "variadic with too few arguments",
+			fn:      func(sep string, parts ...string) string { return strings.Join(parts, sep) },
+			wantErr: "expects at least 1 arguments, got 0",
+			index:   -1,
+		},
+		{
+			name:    "variadic with a wrong type",
+			fn:      func(sep string, parts ...string) string { return strings.Join(parts, sep) },
+			args:    []Value{String("-"), String("a"), Int(1)},
+			wantErr: "argument 3: f: expected string, got int",
+			index:   2,
+		},
+		{
+			name:    "int8 overflow",
+			fn:      func(n int8) int8 { return n },
+			args:    []Value{Int(200)},
+			wantErr: "argument 1: f: 200 does not fit in int8",
+			index:   0,
+		},
+		{
+			name:    "negative uint",
+			fn:      func(n uint) uint { return n },
+			args:    []Value{Int(-1)},
+			wantErr: "argument 1: f: -1 does not fit in uint",
+			index:   0,
+		},
+		{
+			name:    "big int as an int",
+			fn:      func(n int) int { return n },
+			args:    []Value{bigValue("9223372036854775808")},
+			wantErr: "argument 1: f: 9223372036854775808 does not fit in int",
+			index:   0,
+		},
+		{
+			name: "big int as a uint64",
+			fn:   func(n uint64) uint64 { return n },
+			args: []Value{bigValue("18446744073709551615")},
+			want: bigValue("18446744073709551615"),
+		},
+		{
+			name: "big int as a *big.Int",
+			fn:   func(n *big.Int) *big.Int { return n.Add(n, big.NewInt(1)) },
+			args: []Value{bigValue("9223372036854775808")},
+			want: bigValue("9223372036854775809"),
+		},
+		{
+			name: "int as a float",
+			fn:   func(f float64) float64 { return f / 2 },
+			args: []Value{Int(3)},
+			want: Float(1.5),
+		},
+		{
+			name: "interface{} parameters",
+			fn:   func(a, b interface{}) string { return fmt.Sprintf("%T %v, %T %v", a, a, b, b) },
+			args: []Value{&List{Elements: []Value{Int(1), String("a")}}, Nil{}},
+			want: String("[]interface {} [1 a], <nil> <nil>"),
+		},
+		{
+			name: "Value parameter",
+			fn:   func(v Value) string { return v.Type() },
+			args: []Value{Bool(true)},
+			want: String("bool"),
+		},
+		{
+			name: "no result",
+			fn:   func() {},
+			want: Nil{},
+		},
+		{
+			name: "nil error",
+			fn:   func() error { return nil },
+			want: Nil{},
+		},
+		{
+			name: "nil interface{}",
+			fn:   func() (interface{}, error) { return nil, nil },
+			want: Nil{},
+		},
+		{
+			name: "nil slice",
+			fn:   func() []int { return nil },
+			want: Nil{},
+		},
+		{
+			name: "nil *big.Int",
+			fn:   func() *big.Int { return nil },
+			want: Nil{},
+		},
+		{
+			name:    "returned error",
+			fn:      func() (int, error) { return 0, errors.New("failed") },
+			wantErr: "failed",
+			index:   -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			native, err := WrapFunc("f", tt.fn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := native(tt.args)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				var argErr *ArgumentError
+				if isArg := errors.As(err, &argErr); isArg != (tt.index >= 0) || (isArg && argErr.Index != tt.index) {
+					t.Errorf("error %#v, want an ArgumentError of argument %d", err, tt.index)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !interpreter.Equal(got, tt.want) || got.Type() != tt.want.Type() {
+				t.Errorf("got %s, want %s", interpreter.Inspect(got), interpreter.Inspect(tt.want))
+			}
+		})
+	}
+}
+
+func TestWrapFuncRejects(t *testing.T) {
+	tests := []struct {
+		fn   interface{}
+		want string
+	}{
+		{5, "f: expected a function, got int"},
+		{(func())(nil), "f: expected a function, got func()"},
+		{func() (int, int) { return 0, 0 }, "f: a function must give at most a value and an error, func() (int, int) does not"},
+		{func() (int, error, error) { return 0, nil, nil }, "f: a function must give at most a value and an error, func() (int, error, error) does not"},
+	}
+	for _, tt := range tests {
+		if _, err := WrapFunc("f", tt.fn); err == nil || err.Error() != tt.want {
+			t.Errorf("WrapFunc(%T) error = %v, want %q", tt.fn, err, tt.want)
+		}
+	}
+}
+
+func TestRegisterFunc(t *testing.T) {
+	vm := NewVM(Options{})
+	if err := vm.RegisterFunc("add", func(a, b int) int { return a + b }); err != nil {
+		t.Fatal(err)
+	}
+	got, err := vm.Eval(context.Background(), "add(1, 2)")
+	if err != nil || got != Int(3) {
+		t.Errorf("add(1, 2) = %v, %v, want 3", got, err)
+	}
+	for _, src := range []string{"add(1)", `add(1, "2")`, "add(1, 99999999999999999999)"} {
+		if _, err := vm.Eval(context.Background(), src); err == nil {
+			t.Errorf("%s: no error", src)
+		}
+	}
+}
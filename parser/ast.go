@@ -1,36 +1,51 @@
 package parser
 
+import "github.com/AdityaByte/AdiLang/lexer"
+
 type NodeType string
 
 const (
 
 	// Basic Node type
-	NodeProgram NodeType = "PROGRAM"
-	NodePrint NodeType = "PRINT" // out->"hello-world"
+	NodeProgram             NodeType = "PROGRAM"
+	NodePrint               NodeType = "PRINT"                // out->"hello-world"
 	NodeVariableDeclaration NodeType = "VARIABLE_DECLARATION" // var(name="aditya")
-	NodeIfStatement NodeType = "IF_STATEMENT"
-	NodeBlock NodeType = "BLOCK"
-	NodeForLoop NodeType = "FOR_LOOP"
-	NodeRange NodeType = "RANGE"
+	NodeIfStatement         NodeType = "IF_STATEMENT"         // condition, body and an optional else branch
+	NodeBlock               NodeType = "BLOCK"
+	NodeForLoop             NodeType = "FOR_LOOP"   // the first child is a range or the expression of the list or map to iterate, Value is the loop variable or the two variables of "fordude k, v in m"
+	NodeWhileLoop           NodeType = "WHILE_LOOP" // whiledude condition { ... }
+	NodeBreak               NodeType = "BREAK"
+	NodeContinue            NodeType = "CONTINUE"
+	NodeRange               NodeType = "RANGE"                // children are the end, or the start, end and optional step
+	NodeFunctionDeclaration NodeType = "FUNCTION_DECLARATION" // fundude add(a, b) { ... }, children are the parameters and the body
+	NodeParameters          NodeType = "PARAMETERS"
+	NodeReturn              NodeType = "RETURN"               // return a + b, the value child is optional
+	NodeExpressionStatement NodeType = "EXPRESSION_STATEMENT" // a call used as a statement
+	NodeAssignment          NodeType = "ASSIGNMENT"           // x = 5, x += 1, Value is the operator
 
 	// Expression Node type
-	NodeStringLiteral NodeType = "STRING_LITERAL"
-	NodeNumberLiteral NodeType = "NUMBER_LITERAL"
-	NodeIdentifier NodeType = "IDENTIFIER"
-	NodeBinaryOperation NodeType = "BINARY_OPERATION"
-
-	// Operator Node type
-	NodeComparision NodeType = "COMPARISION"
-	NodeGreaterThan NodeType = "GREATERTHAN"
-	NodeLessThan NodeType = "LESSTHAN"
-	NodeNotEquals NodeType = "NOTEQUALS"
-
-	// Condition node type
-	NodeCondition NodeType = "CONDITION"
+	NodeStringLiteral   NodeType = "STRING_LITERAL"
+	NodeNumberLiteral   NodeType = "NUMBER_LITERAL"
+	NodeBooleanLiteral  NodeType = "BOOLEAN_LITERAL"
+	NodeIdentifier      NodeType = "IDENTIFIER"
+	NodeBinaryOperation NodeType = "BINARY_OPERATION" // Value is the operator, Children are the left and right side
+	NodeUnaryOperation  NodeType = "UNARY_OPERATION"  // -x, !x
+	NodeCall            NodeType = "CALL"             // the first child is the callee, the rest are the arguments
+	NodeFunctionLiteral NodeType = "FUNCTION_LITERAL" // fundude(a) { ... } used as a value
+	NodeListLiteral     NodeType = "LIST_LITERAL"     // [1, 2, "x"]
+	NodeMapLiteral      NodeType = "MAP_LITERAL"      // {"a": 1}, children are key, value, key, value...
+	NodeIndex           NodeType = "INDEX"            // xs[i]
+	NodeSlice           NodeType = "SLICE"            // xs[start:end], a missing bound is nil
+	NodeMember          NodeType = "MEMBER"           // math.sqrt, Value is the name and the child is the module
+	NodeInterpolation   NodeType = "INTERPOLATION"    // "Hello ${name}", children are the text parts and the expressions in order
 )
 
+// Start is the position of the first token of the node and End is
+// the position just after its last token.
 type ASTNode struct {
-	Type NodeType
-	Value interface{}
-	Children []*ASTNode
-}
\ No newline at end of file
+	Type     NodeType       `json:"type"`
+	Value    interface{}    `json:"value,omitempty"`
+	Children []*ASTNode     `json:"children,omitempty"`
+	Start    lexer.Position `json:"start"`
+	End      lexer.Position `json:"end"`
+}
@@ -0,0 +1,420 @@
+package parser
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/AdityaByte/AdiLang/diagnostics"
+	"github.com/AdityaByte/AdiLang/lexer"
+)
+
+// Operator precedences, from the loosest to the tightest binding.
+const (
+	precedenceLowest      = iota
+	precedenceOr          // ||
+	precedenceAnd         // &&
+	precedenceEquals      // == !=
+	precedenceLessGreater // < > <= >=
+	precedenceSum         // + -
+	precedenceProduct     // * / %
+	precedencePrefix      // -x !x
+	precedenceCall        // add(1, 2), xs[0]
+)
+
+var precedences = map[lexer.TokenType]int{
+	lexer.OrOperator:           precedenceOr,
+	lexer.AndOperator:          precedenceAnd,
+	lexer.ComparisionOperator:  precedenceEquals,
+	lexer.NotEqualsOperator:    precedenceEquals,
+	lexer.GreaterThanOperator:  precedenceLessGreater,
+	lexer.LessThanOperator:     precedenceLessGreater,
+	lexer.GreaterEqualOperator: precedenceLessGreater,
+	lexer.LessEqualOperator:    precedenceLessGreater,
+	lexer.PlusOperator:         precedenceSum,
+	lexer.MinusOperator:        precedenceSum,
+	lexer.MultiplyOperator:     precedenceProduct,
+	lexer.DivideOperator:       precedenceProduct,
+	lexer.ModuloOperator:       precedenceProduct,
+	lexer.LParen:               precedenceCall,
+	lexer.LBracket:             precedenceCall,
+	lexer.Dot:                  precedenceCall,
+}
+
+// parseExpression parses a full expression with operators, e.g. (a + 2) * -b > 10 && !done
+func (p *Parser) parseExpression() (*ASTNode, error) {
+	return p.parseExpressionWithPrecedence(precedenceLowest)
+}
+
+// parseExpressionWithPrecedence is the pratt parser loop, it keeps on taking binary operators
+// as long as they bind tighter than the given precedence.
+func (p *Parser) parseExpressionWithPrecedence(precedence int) (*ASTNode, error) {
+	left, err := p.parsePrefix()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		operatorPrecedence, ok := precedences[p.currentToken().Type]
+		if !ok || operatorPrecedence <= precedence {
+			return left, nil
+		}
+
+		switch p.currentToken().Type {
+		case lexer.LParen:
+			left, err = p.parseCallExpression(left)
+		case lexer.LBracket:
+			left, err = p.parseIndexExpression(left)
+		case lexer.Dot:
+			left, err = p.parseMemberExpression(left)
+		default:
+			left, err = p.parseBinaryOperation(left, operatorPrecedence)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parsePrefix parses the things which can start an expression.
+func (p *Parser) parsePrefix() (*ASTNode, error) {
+	switch p.currentToken().Type {
+	case lexer.StringLiteral:
+		return p.parseStringLiteral()
+	case lexer.StringStart:
+		return p.parseInterpolation()
+	case lexer.NumberLiteral:
+		return p.parseNumberLiteral()
+	case lexer.Identifier:
+		// An assignment is never part of an expression, so in "a = \n b = 5" the value of a
+		// is missing instead of being b.
+		next := p.peekToken().Type
+		if p.Pos != p.statementStart && (next == lexer.AssignOperator || next == lexer.CompoundAssignOperator) {
+			return nil, p.missingExpression()
+		}
+		return p.parseIdentifier()
+	case lexer.BooleanLiteral:
+		return p.parseBooleanLiteral()
+	case lexer.LParen:
+		return p.parseGroupedExpression()
+	case lexer.MinusOperator, lexer.NotOperator:
+		return p.parseUnaryOperation()
+	case lexer.FunDudeKeyword:
+		return p.parseFunctionLiteral()
+	case lexer.LBracket:
+		return p.parseListLiteral()
+	case lexer.LBrace:
+		// A '{' where an expression is expected is a map, blocks only start at statements.
+		return p.parseMapLiteral()
+	default:
+		if p.Pos >= len(p.Tokens) {
+			return nil, p.errorf(diagnostics.CodeUnexpectedEOF, "Expected Expression but the file ended")
+		}
+		if p.currentToken().Type == lexer.IllegalToken {
+			return nil, p.unexpectedToken()
+		}
+		return nil, p.missingExpression()
+	}
+}
+
+// missingExpression reports an expression which is not there. When the current token is
+// on a later line the expression is missing at the end of the line before, like in "x = ".
+func (p *Parser) missingExpression() error {
+	if p.Pos > 0 && p.currentToken().Pos.Line > p.lastEnd().Line {
+		end := p.lastEnd()
+		return diagnostics.New(diagnostics.CodeExpectedToken, end, end, "Expected Expression at the end of the line")
+	}
+	return p.errorf(diagnostics.CodeExpectedToken, "Expected Expression (string, number, identifier or '(')")
+}
+
+func (p *Parser) parseBinaryOperation(left *ASTNode, precedence int) (*ASTNode, error) {
+	operator := p.currentToken().Value
+	p.nextToken()
+
+	// Parsing the right side with the same precedence makes the operators left associative.
+	right, err := p.parseExpressionWithPrecedence(precedence)
+	if err != nil {
+		// A % right after a value used to start a comment, like in out->x % note %.
+		var d *diagnostics.Diagnostic
+		if operator == "%" && errors.As(err, &d) && d.Help == "" {
+			d.WithHelp("a '%%' right after a value is the modulo operator, write the comment with // instead")
+		}
+		return nil, err
+	}
+
+	return &ASTNode{
+		Type:     NodeBinaryOperation,
+		Value:    operator,
+		Children: []*ASTNode{left, right},
+		Start:    left.Start,
+		End:      right.End,
+	}, nil
+}
+
+func (p *Parser) parseUnaryOperation() (*ASTNode, error) {
+	token := p.currentToken()
+	p.nextToken()
+
+	// A minus in front of a number makes a negative literal instead of a unary operation.
+	if token.Type == lexer.MinusOperator && p.currentToken().Type == lexer.NumberLiteral {
+		return p.parseNegativeNumber(token)
+	}
+
+	operand, err := p.parseExpressionWithPrecedence(precedencePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ASTNode{
+		Type:     NodeUnaryOperation,
+		Value:    token.Value,
+		Children: []*ASTNode{operand},
+		Start:    token.Pos,
+		End:      operand.End,
+	}, nil
+}
+
+func (p *Parser) parseNegativeNumber(minus lexer.Token) (*ASTNode, error) {
+	node, err := p.parseNumberLiteral()
+	if err != nil {
+		return nil, err
+	}
+	switch value := node.Value.(type) {
+	case int64:
+		node.Value = -value
+	case float64:
+		node.Value = -value
+	case *big.Int:
+		node.Value = value.Neg(value)
+	}
+	node.Start = minus.Pos
+	return node, nil
+}
+
+// parseInterpolation parses a string like "Hello ${name}!", the lexer gives the text before,
+// between and after the expressions as StringStart, StringMiddle and StringEnd tokens.
+func (p *Parser) parseInterpolation() (*ASTNode, error) {
+	start := p.currentToken().Pos
+	var parts []*ASTNode
+
+	for {
+		token := p.currentToken()
+		// Empty text, like before ${a} in "${a}", is left out.
+		if token.Value != "" {
+			parts = append(parts, &ASTNode{
+				Type:  NodeStringLiteral,
+				Value: token.Value,
+				Start: token.Pos,
+				End:   token.End,
+			})
+		}
+		p.nextToken()
+		if token.Type == lexer.StringEnd {
+			break
+		}
+
+		expr, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, expr)
+
+		switch p.currentToken().Type {
+		case lexer.StringMiddle, lexer.StringEnd:
+		case lexer.IllegalToken:
+			return nil, p.unexpectedToken()
+		default:
+			return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected '}' to close the interpolation")
+		}
+	}
+
+	return &ASTNode{
+		Type:     NodeInterpolation,
+		Children: parts,
+		Start:    start,
+		End:      p.lastEnd(),
+	}, nil
+}
+
+// parseGroupedExpression parses an expression inside parenthesis, the parenthesis only
+// change the shape of the tree so no node is made for them.
+func (p *Parser) parseGroupedExpression() (*ASTNode, error) {
+	p.nextToken()
+
+	expr, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.currentToken().Type != lexer.RParen {
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected ')' closing paranthesis")
+	}
+	p.nextToken()
+	return expr, nil
+}
+
+// parseCallExpression parses the arguments of a call, the callee is what comes before the '('.
+func (p *Parser) parseCallExpression(callee *ASTNode) (*ASTNode, error) {
+	p.nextToken()
+
+	children := []*ASTNode{callee}
+	for p.currentToken().Type != lexer.RParen {
+		arg, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, arg)
+
+		if p.currentToken().Type != lexer.Comma {
+			break
+		}
+		p.nextToken()
+	}
+
+	if p.currentToken().Type != lexer.RParen {
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected ',' or ')' in the argument list")
+	}
+	p.nextToken()
+
+	return &ASTNode{
+		Type:     NodeCall,
+		Children: children,
+		Start:    callee.Start,
+		End:      p.lastEnd(),
+	}, nil
+}
+
+// parseListLiteral parses [1, 2, "x"], a comma after the last element is allowed.
+func (p *Parser) parseListLiteral() (*ASTNode, error) {
+	start := p.currentToken().Pos
+	p.nextToken()
+
+	var elements []*ASTNode
+	for p.currentToken().Type != lexer.RBracket {
+		element, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, element)
+
+		if p.currentToken().Type != lexer.Comma {
+			break
+		}
+		p.nextToken()
+	}
+
+	if p.currentToken().Type != lexer.RBracket {
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected ',' or ']' in the list")
+	}
+	p.nextToken()
+
+	return &ASTNode{
+		Type:     NodeListLiteral,
+		Children: elements,
+		Start:    start,
+		End:      p.lastEnd(),
+	}, nil
+}
+
+// parseMapLiteral parses {"a": 1, "b": 2}, the children are the keys and values one after another.
+func (p *Parser) parseMapLiteral() (*ASTNode, error) {
+	start := p.currentToken().Pos
+	p.nextToken()
+
+	var entries []*ASTNode
+	for p.currentToken().Type != lexer.RBrace {
+		key, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.currentToken().Type != lexer.Colon {
+			return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected ':' after the map key")
+		}
+		p.nextToken()
+
+		value, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, key, value)
+
+		if p.currentToken().Type != lexer.Comma {
+			break
+		}
+		p.nextToken()
+	}
+
+	if p.currentToken().Type != lexer.RBrace {
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected ',' or '}' in the map")
+	}
+	p.nextToken()
+
+	return &ASTNode{
+		Type:     NodeMapLiteral,
+		Children: entries,
+		Start:    start,
+		End:      p.lastEnd(),
+	}, nil
+}
+
+// parseMemberExpression parses module.name, like math.sqrt.
+func (p *Parser) parseMemberExpression(target *ASTNode) (*ASTNode, error) {
+	p.nextToken()
+
+	token := p.currentToken()
+	if token.Type != lexer.Identifier {
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected a name after '.'")
+	}
+	p.nextToken()
+
+	return &ASTNode{
+		Type:     NodeMember,
+		Value:    token.Value,
+		Children: []*ASTNode{target},
+		Start:    target.Start,
+		End:      token.End,
+	}, nil
+}
+
+// parseIndexExpression parses xs[i] and the slices xs[start:end], where both bounds
+// of the slice can be left out. A missing bound is a nil child.
+func (p *Parser) parseIndexExpression(target *ASTNode) (*ASTNode, error) {
+	p.nextToken()
+
+	var index *ASTNode
+	var err error
+	if p.currentToken().Type != lexer.Colon {
+		index, err = p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	node := &ASTNode{
+		Type:     NodeIndex,
+		Children: []*ASTNode{target, index},
+		Start:    target.Start,
+	}
+
+	if p.currentToken().Type == lexer.Colon {
+		p.nextToken()
+
+		var end *ASTNode
+		if p.currentToken().Type != lexer.RBracket {
+			end, err = p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+		}
+		node.Type = NodeSlice
+		node.Children = append(node.Children, end)
+	}
+
+	if p.currentToken().Type != lexer.RBracket {
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected ']'")
+	}
+	p.nextToken()
+
+	node.End = p.lastEnd()
+	return node, nil
+}
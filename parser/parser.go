@@ -1,48 +1,91 @@
 package parser
 
 import (
-	"fmt"
+	"errors"
+	"math/big"
 	"strconv"
+	"strings"
 
+	"github.com/AdityaByte/AdiLang/diagnostics"
 	"github.com/AdityaByte/AdiLang/lexer"
 )
 
 type Parser struct {
 	Tokens []lexer.Token
 	Pos    int
+
+	errors []*diagnostics.Diagnostic
+	// functionDepth tells if we are inside a function body where return is allowed,
+	// loopDepth does the same for break and continue.
+	functionDepth int
+	loopDepth     int
+	// statementStart is where the current expression statement began, the target of an assignment.
+	statementStart int
 }
 
 func (p *Parser) currentToken() lexer.Token {
 	if p.Pos < len(p.Tokens) {
 		return p.Tokens[p.Pos]
 	}
-	return lexer.Token{lexer.IllegalToken, ""}
+	// Past the last token we are at the end of the file.
+	end := p.lastEnd()
+	return lexer.Token{Type: lexer.IllegalToken, Value: "", Pos: end, End: end}
 }
 
 func (p *Parser) nextToken() {
 	p.Pos++
 }
 
+// peekToken gives the token after the current one.
+func (p *Parser) peekToken() lexer.Token {
+	if p.Pos+1 < len(p.Tokens) {
+		return p.Tokens[p.Pos+1]
+	}
+	end := p.lastEnd()
+	return lexer.Token{Type: lexer.IllegalToken, Value: "", Pos: end, End: end}
+}
+
+// lastEnd returns the end position of the last consumed token.
+func (p *Parser) lastEnd() lexer.Position {
+	if len(p.Tokens) == 0 {
+		return lexer.Position{Line: 1, Column: 1}
+	}
+	if p.Pos == 0 {
+		return p.Tokens[0].Pos
+	}
+	if p.Pos > len(p.Tokens) {
+		return p.Tokens[len(p.Tokens)-1].End
+	}
+	return p.Tokens[p.Pos-1].End
+}
+
+// errorf creates a diagnostic pointing at the current token.
+func (p *Parser) errorf(code string, format string, args ...interface{}) *diagnostics.Diagnostic {
+	token := p.currentToken()
+	return diagnostics.New(code, token.Pos, token.End, format, args...)
+}
+
 // for parsing the variable declaration.
 func (p *Parser) parseVariableDeclaration() (*ASTNode, error) {
+	start := p.currentToken().Pos
 	if p.currentToken().Type != lexer.VarKeyword {
-		return nil, fmt.Errorf("Expected 'var' keyword")
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected 'var' keyword")
 	}
 	p.nextToken()
 
 	if p.currentToken().Type != lexer.LParen {
-		return nil, fmt.Errorf("Expected '(' keyword")
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected '(' keyword")
 	}
 	p.nextToken()
 
 	if p.currentToken().Type != lexer.Identifier {
-		return nil, fmt.Errorf("Expected 'identifier'")
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected 'identifier'")
 	}
 	ident := p.currentToken().Value
 	p.nextToken()
 
 	if p.currentToken().Type != lexer.AssignOperator {
-		return nil, fmt.Errorf("Expected '=' keyword")
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected '=' keyword")
 	}
 	p.nextToken()
 
@@ -52,7 +95,7 @@ func (p *Parser) parseVariableDeclaration() (*ASTNode, error) {
 	}
 
 	if p.currentToken().Type != lexer.RParen {
-		return nil, fmt.Errorf("Expected ')' closing paranthesis")
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected ')' closing paranthesis")
 	}
 	p.nextToken()
 
@@ -60,19 +103,21 @@ func (p *Parser) parseVariableDeclaration() (*ASTNode, error) {
 		Type:     NodeVariableDeclaration,
 		Value:    ident,
 		Children: []*ASTNode{expr},
+		Start:    start,
+		End:      p.lastEnd(),
 	}, nil
 }
 
 // for parsing the print statement.
 func (p *Parser) parsePrintStatement() (*ASTNode, error) {
+	start := p.currentToken().Pos
 	if p.currentToken().Type != lexer.OutKeyword {
-		return nil, fmt.Errorf("Expected 'out' keyword")
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected 'out' keyword")
 	}
 	p.nextToken()
 
 	if p.currentToken().Type != lexer.PrintOperator {
-		fmt.Println("Expected '->'")
-		return nil, fmt.Errorf("Expected '->' keyword")
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected '->' keyword")
 	}
 	p.nextToken()
 
@@ -81,37 +126,23 @@ func (p *Parser) parsePrintStatement() (*ASTNode, error) {
 	if err != nil {
 		return nil, err
 	}
-	
-	// fmt.Println(p.currentToken().Type, p.currentToken().Value)
-	if p.currentToken().Type == lexer.PlusOperator {
-		p.nextToken()
-		anotherExpr, err := p.parseExpression()
-		if err != nil {
-			return nil, err
-		}
-
-		return &ASTNode{
-			Type: NodePrint,
-			Value: expr,
-			Children: []*ASTNode{
-				anotherExpr, // Children at zero index
-			},
-		}, nil
-	}
 
 	return &ASTNode{
 		Type:  NodePrint,
 		Value: expr,
+		Start: start,
+		End:   p.lastEnd(),
 	}, nil
 }
 
 func (p *Parser) parseIfStatement() (*ASTNode, error) {
+	start := p.currentToken().Pos
 	if p.currentToken().Type != lexer.IfKeyword {
-		return nil, fmt.Errorf("Expected if keyword")
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected if keyword")
 	}
 	p.nextToken()
 
-	cond, err := p.parseCondition()
+	cond, err := p.parseExpression()
 	if err != nil {
 		return nil, err
 	}
@@ -122,139 +153,118 @@ func (p *Parser) parseIfStatement() (*ASTNode, error) {
 		return nil, err
 	}
 
-	return &ASTNode{
-		Type: NodeIfStatement,
-		Children: []*ASTNode{
-			cond,
-			body,
-		},
-	}, nil
-}
-
-func (p *Parser) parseCondition() (*ASTNode, error) {
-	left, err := p.parsePrimary()
-
-	if err != nil {
-		return nil, err
-	}
-
-	// fmt.Println("left value : ", left.Value)
-
-	operator, err := p.parseOperator()
-	if err != nil {
-		return nil, err
+	children := []*ASTNode{
+		cond,
+		body,
 	}
-	operatorValue := operator.Value
 
-	// fmt.Println("Operator Value:", operatorValue)
-
-	right, err := p.parsePrimary()
-
-	// fmt.Println("Right Value :", right.Value)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return &ASTNode{
-		Type:  NodeCondition,
-		Value: operatorValue,
-		Children: []*ASTNode{
-			left,
-			right,
-		},
-	}, nil
-}
-
-func (p *Parser) parseOperator() (*ASTNode, error) {
-	switch p.currentToken().Type {
-	case lexer.ComparisionOperator:
-		node := &ASTNode{
-			Type:  NodeComparision,
-			Value: p.currentToken().Value,
-		}
-		p.nextToken()
-		return node, nil
-	case lexer.GreaterThanOperator:
-		node := &ASTNode{
-			Type:  NodeGreaterThan,
-			Value: p.currentToken().Value,
-		}
+	// The optional else branch is the third child, it is either a block or
+	// another if statement for the else ifdude chains.
+	if p.currentToken().Type == lexer.ElseKeyword {
 		p.nextToken()
-		return node, nil
-	case lexer.LessThanOperator:
-		node := &ASTNode{
-			Type:  NodeLessThan,
-			Value: p.currentToken().Value,
+
+		var elseBranch *ASTNode
+		if p.currentToken().Type == lexer.IfKeyword {
+			elseBranch, err = p.parseIfStatement()
+		} else {
+			elseBranch, err = p.parseBlock()
 		}
-		p.nextToken()
-		return node, nil
-	case lexer.NotEqualsOperator:
-		node := &ASTNode{
-			Type: NodeNotEquals,
-			Value: p.currentToken().Value,
+		if err != nil {
+			return nil, err
 		}
-		p.nextToken()
-		return node, nil
-	default:
-		return nil, fmt.Errorf("Expected these '==, >, <'")
+		children = append(children, elseBranch)
 	}
-}
 
-func (p *Parser) parsePrimary() (*ASTNode, error) {
-	switch p.currentToken().Type {
-	case lexer.NumberLiteral:
-		return p.parseNumberLiteral()
-	case lexer.Identifier:
-		return p.parseIdentifier()
-	default:
-		return nil, fmt.Errorf("Expected number, identifier")
-	}
+	return &ASTNode{
+		Type:     NodeIfStatement,
+		Children: children,
+		Start:    start,
+		End:      p.lastEnd(),
+	}, nil
 }
 
 func (p *Parser) parseForLoop() (*ASTNode, error) {
+	start := p.currentToken().Pos
 	if p.currentToken().Type != lexer.ForDudeKeyword {
-		return nil, fmt.Errorf("Expected 'fordude' keyword")
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected 'fordude' keyword")
 	}
 	p.nextToken()
 
 	if p.currentToken().Type != lexer.Identifier {
-		return nil, fmt.Errorf("Expected identifier")
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected identifier")
 	}
-	loopVar := p.currentToken().Value
+	var loopVar interface{} = p.currentToken().Value
 	p.nextToken()
 
+	// fordude k, v in m has two loop variables.
+	if p.currentToken().Type == lexer.Comma {
+		p.nextToken()
+		if p.currentToken().Type != lexer.Identifier {
+			return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected identifier")
+		}
+		loopVar = []string{loopVar.(string), p.currentToken().Value}
+		p.nextToken()
+	}
+
 	if p.currentToken().Type != lexer.InKeyword {
-		return nil, fmt.Errorf("Expected 'in' keyword")
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected 'in' keyword")
 	}
 	p.nextToken()
 
+	// Without range the loop goes over the elements of a list or the keys of a map.
 	if p.currentToken().Type != lexer.RangeKeyword {
-		return nil, fmt.Errorf("Expected 'range' keyword")
+		iterable, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := p.parseLoopBody()
+		if err != nil {
+			return nil, err
+		}
+
+		return &ASTNode{
+			Type:     NodeForLoop,
+			Value:    loopVar,
+			Children: []*ASTNode{iterable, body},
+			Start:    start,
+			End:      p.lastEnd(),
+		}, nil
+	}
+
+	if _, ok := loopVar.(string); !ok {
+		return nil, p.errorf(diagnostics.CodeUnexpectedToken, "range loops take a single loop variable")
 	}
+	rangeStart := p.currentToken().Pos
 	p.nextToken()
 
 	if p.currentToken().Type != lexer.LParen {
-		return nil, fmt.Errorf("Expected '(' keyword")
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected '(' keyword")
 	}
 	p.nextToken()
 
-	if p.currentToken().Type != lexer.NumberLiteral {
-		return nil, fmt.Errorf("Expected number literal")
-	}
-	limit, err := strconv.Atoi(p.currentToken().Value)
+	// range(end), range(start, end) or range(start, end, step)
+	var bounds []*ASTNode
+	for {
+		bound, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		bounds = append(bounds, bound)
 
-	if err != nil {
-		return nil, fmt.Errorf("invalid number: %s", p.currentToken().Value)
+		if p.currentToken().Type != lexer.Comma || len(bounds) == 3 {
+			break
+		}
+		p.nextToken()
 	}
-	p.nextToken()
 
 	if p.currentToken().Type != lexer.RParen {
-		return nil, fmt.Errorf("Expected ')' keyword")
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected ')' keyword, range takes at most 3 values")
 	}
 	p.nextToken()
+	rangeEnd := p.lastEnd()
 
-	body, err := p.parseBlock()
+	body, err := p.parseLoopBody()
 	if err != nil {
 		return nil, err
 	}
@@ -264,40 +274,58 @@ func (p *Parser) parseForLoop() (*ASTNode, error) {
 		Value: loopVar,
 		Children: []*ASTNode{
 			{
-				Type:  NodeRange,
-				Value: limit,
+				Type:     NodeRange,
+				Children: bounds,
+				Start:    rangeStart,
+				End:      rangeEnd,
 			},
 			body,
 		},
+		Start: start,
+		End:   p.lastEnd(),
 	}, nil
 
 }
 
 func (p *Parser) parseBlock() (*ASTNode, error) {
+	start := p.currentToken().Pos
 	// fmt.Println("current token in block:", p.currentToken().Value)
 	if p.currentToken().Type != lexer.LBrace {
-		return nil, fmt.Errorf("Expected 'if' keyword")
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected '{'")
 	}
+	open := p.currentToken()
 	p.nextToken()
 
 	var statements []*ASTNode
 
 	for p.currentToken().Type != lexer.RBrace {
+		if p.Pos >= len(p.Tokens) {
+			// Pointing at the opening brace tells more than pointing at the end of the file.
+			p.errors = append(p.errors, diagnostics.New(diagnostics.CodeUnexpectedEOF, open.Pos, open.End, "expected '}' to close this block"))
+			return &ASTNode{
+				Type:     NodeBlock,
+				Children: statements,
+				Start:    start,
+				End:      p.lastEnd(),
+			}, nil
+		}
+
+		stmtPos := p.Pos
 		stmt, err := p.parseStatement()
 		if err != nil {
-			return nil, err
+			p.recordError(err)
+			p.synchronize(stmtPos)
+			continue
 		}
 		statements = append(statements, stmt)
 	}
-
-	if p.currentToken().Type != lexer.RBrace {
-		return nil, fmt.Errorf("expected'}'")
-	}
 	p.nextToken()
 
 	return &ASTNode{
 		Type:     NodeBlock,
 		Children: statements,
+		Start:    start,
+		End:      p.lastEnd(),
 	}, nil
 }
 
@@ -311,118 +339,454 @@ func (p *Parser) parseStatement() (*ASTNode, error) {
 		return p.parseForLoop()
 	case lexer.IfKeyword:
 		return p.parseIfStatement()
+	case lexer.LBrace:
+		return p.parseBlock()
+	case lexer.FunDudeKeyword:
+		return p.parseFunctionDeclaration()
+	case lexer.ReturnKeyword:
+		return p.parseReturnStatement()
+	case lexer.WhileDudeKeyword:
+		return p.parseWhileLoop()
+	case lexer.BreakKeyword, lexer.ContinueKeyword:
+		return p.parseLoopControl()
+	case lexer.Identifier:
+		return p.parseExpressionStatement()
 	default:
-		return nil, fmt.Errorf("unexpected token: %v", p.currentToken())
+		return nil, p.unexpectedToken()
 	}
 }
 
-func (p *Parser) parseExpression() (*ASTNode, error) {
-	switch p.currentToken().Type {
-	case lexer.StringLiteral:
-		return p.parseStringLiteral()
-	case lexer.NumberLiteral:
-		return p.parseNumberLiteral()
-	case lexer.Identifier:
-		return p.parseIdentifier()
-	default:
-		return nil, fmt.Errorf("Expected Expression (string, number or identifier)")
+// for parsing the function declaration: fundude add(a, b) { ... }
+func (p *Parser) parseFunctionDeclaration() (*ASTNode, error) {
+	start := p.currentToken().Pos
+	p.nextToken()
+
+	if p.currentToken().Type != lexer.Identifier {
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected function name")
+	}
+	name := p.currentToken().Value
+	p.nextToken()
+
+	params, err := p.parseParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.parseFunctionBody()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ASTNode{
+		Type:     NodeFunctionDeclaration,
+		Value:    name,
+		Children: []*ASTNode{params, body},
+		Start:    start,
+		End:      p.lastEnd(),
+	}, nil
+}
+
+// for parsing an anonymous function used as a value: fundude(a, b) { ... }
+func (p *Parser) parseFunctionLiteral() (*ASTNode, error) {
+	start := p.currentToken().Pos
+	p.nextToken()
+
+	params, err := p.parseParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.parseFunctionBody()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ASTNode{
+		Type:     NodeFunctionLiteral,
+		Children: []*ASTNode{params, body},
+		Start:    start,
+		End:      p.lastEnd(),
+	}, nil
+}
+
+// parseParameters parses the parameter names of a function: (a, b)
+func (p *Parser) parseParameters() (*ASTNode, error) {
+	start := p.currentToken().Pos
+	if p.currentToken().Type != lexer.LParen {
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected '(' keyword")
+	}
+	p.nextToken()
+
+	var params []*ASTNode
+	seen := map[string]bool{}
+	for p.currentToken().Type == lexer.Identifier {
+		if seen[p.currentToken().Value] {
+			return nil, p.errorf(diagnostics.CodeUnexpectedToken, "duplicate parameter: %s", p.currentToken().Value)
+		}
+		seen[p.currentToken().Value] = true
+
+		param, err := p.parseIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, param)
+
+		if p.currentToken().Type != lexer.Comma {
+			break
+		}
+		p.nextToken()
+	}
+
+	if p.currentToken().Type != lexer.RParen {
+		return nil, p.errorf(diagnostics.CodeExpectedToken, "Expected ')' or a parameter name")
+	}
+	p.nextToken()
+
+	return &ASTNode{
+		Type:     NodeParameters,
+		Children: params,
+		Start:    start,
+		End:      p.lastEnd(),
+	}, nil
+}
+
+func (p *Parser) parseFunctionBody() (*ASTNode, error) {
+	// A loop around the function does not make break valid inside of it.
+	loopDepth := p.loopDepth
+	p.loopDepth = 0
+	p.functionDepth++
+	defer func() {
+		p.functionDepth--
+		p.loopDepth = loopDepth
+	}()
+	return p.parseBlock()
+}
+
+func (p *Parser) parseLoopBody() (*ASTNode, error) {
+	p.loopDepth++
+	defer func() { p.loopDepth-- }()
+	return p.parseBlock()
+}
+
+// for parsing the while loop: whiledude condition { ... }
+func (p *Parser) parseWhileLoop() (*ASTNode, error) {
+	start := p.currentToken().Pos
+	p.nextToken()
+
+	cond, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := p.parseLoopBody()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ASTNode{
+		Type:     NodeWhileLoop,
+		Children: []*ASTNode{cond, body},
+		Start:    start,
+		End:      p.lastEnd(),
+	}, nil
+}
+
+// for parsing break and continue, both are only allowed inside a loop.
+func (p *Parser) parseLoopControl() (*ASTNode, error) {
+	token := p.currentToken()
+	if p.loopDepth == 0 {
+		return nil, p.errorf(diagnostics.CodeUnexpectedToken, "%s outside of a loop", token.Value)
+	}
+	p.nextToken()
+
+	nodeType := NodeBreak
+	if token.Type == lexer.ContinueKeyword {
+		nodeType = NodeContinue
+	}
+	return &ASTNode{
+		Type:  nodeType,
+		Start: token.Pos,
+		End:   token.End,
+	}, nil
+}
+
+// for parsing the return statement, the value has to start on the same line as the return.
+func (p *Parser) parseReturnStatement() (*ASTNode, error) {
+	token := p.currentToken()
+	if p.functionDepth == 0 {
+		return nil, p.errorf(diagnostics.CodeUnexpectedToken, "return outside of a function")
+	}
+	p.nextToken()
+
+	node := &ASTNode{
+		Type:  NodeReturn,
+		Start: token.Pos,
+	}
+
+	next := p.currentToken()
+	if p.Pos < len(p.Tokens) && next.Pos.Line == token.Pos.Line && next.Type != lexer.RBrace {
+		value, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		node.Children = []*ASTNode{value}
+	}
+
+	node.End = p.lastEnd()
+	return node, nil
+}
+
+// parseExpressionStatement parses the statements starting with an expression, that is an
+// assignment like x = 5 or count += 1, or a call used as a statement like greet("adi").
+// Any other expression would do nothing so it is reported as an error.
+func (p *Parser) parseExpressionStatement() (*ASTNode, error) {
+	stmtPos := p.Pos
+	defer func(previous int) { p.statementStart = previous }(p.statementStart)
+	p.statementStart = stmtPos
+	expr, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.currentToken().Type == lexer.AssignOperator || p.currentToken().Type == lexer.CompoundAssignOperator {
+		return p.parseAssignment(expr)
+	}
+
+	if expr.Type != NodeCall {
+		// Going back so the error points at the start of the statement.
+		p.Pos = stmtPos
+		return nil, p.unexpectedToken()
+	}
+
+	return &ASTNode{
+		Type:     NodeExpressionStatement,
+		Children: []*ASTNode{expr},
+		Start:    expr.Start,
+		End:      expr.End,
+	}, nil
+}
+
+// parseAssignment parses the value of an assignment, target is the left side of it.
+func (p *Parser) parseAssignment(target *ASTNode) (*ASTNode, error) {
+	operator := p.currentToken()
+	if target.Type != NodeIdentifier && target.Type != NodeIndex {
+		return nil, diagnostics.New(diagnostics.CodeUnexpectedToken, target.Start, target.End, "cannot assign to this expression")
+	}
+	p.nextToken()
+
+	value, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ASTNode{
+		Type:     NodeAssignment,
+		Value:    operator.Value,
+		Children: []*ASTNode{target, value},
+		Start:    target.Start,
+		End:      value.End,
+	}, nil
+}
+
+// unexpectedToken reports the current token as not allowed here,
+// with a hint when it looks like a misspelled keyword.
+func (p *Parser) unexpectedToken() *diagnostics.Diagnostic {
+	token := p.currentToken()
+	if p.Pos >= len(p.Tokens) {
+		return p.errorf(diagnostics.CodeUnexpectedEOF, "unexpected end of file")
+	}
+
+	if token.Error == lexer.ErrUnterminatedComment {
+		return p.errorf(diagnostics.CodeInvalidComment, "%s", token.Error).
+			WithHelp("close the comment with a '%%', or use // for a comment up to the end of the line")
+	}
+	if token.Error != "" {
+		return p.errorf(diagnostics.CodeInvalidString, "%s", token.Error)
+	}
+
+	err := p.errorf(diagnostics.CodeUnexpectedToken, "unexpected token: %q", token.Value)
+	if token.Type == lexer.Identifier {
+		if keyword, ok := lexer.SuggestKeyword(token.Value); ok {
+			err.WithHelp("did you mean `%s`?", keyword)
+		}
+	}
+	return err
+}
+
+// recordError keeps the error so parsing can go on and report the next ones too.
+func (p *Parser) recordError(err error) {
+	if diag, ok := err.(*diagnostics.Diagnostic); ok {
+		p.errors = append(p.errors, diag)
+		return
+	}
+	token := p.currentToken()
+	p.errors = append(p.errors, diagnostics.New(diagnostics.CodeUnexpectedToken, token.Pos, token.End, "%v", err))
+}
+
+// synchronize skips tokens after a syntax error until a point where a new statement can start,
+// that is a statement keyword, a brace or the first token of a new line, as most statements
+// are on their own line. stmtPos is where the failed statement began, at least one token is
+// skipped when nothing was consumed so the parser always makes progress.
+func (p *Parser) synchronize(stmtPos int) {
+	// The statement failed on the line of its last token, or of the current one when nothing was consumed.
+	line := p.currentToken().Pos.Line
+	if p.Pos > stmtPos {
+		line = p.lastEnd().Line
+	} else {
+		p.nextToken()
+	}
+
+	// A '{' opened by the failed statement, like the one of "ifdude 1 > { out->1 }" which was
+	// read as a map, is skipped up to its '}' so that '}' is not reported as another error.
+	depth := 0
+	for _, token := range p.Tokens[stmtPos:p.Pos] {
+		switch token.Type {
+		case lexer.LBrace:
+			depth++
+		case lexer.RBrace:
+			depth--
+		}
+	}
+	if depth > 0 {
+		for depth > 0 && p.Pos < len(p.Tokens) {
+			switch p.currentToken().Type {
+			case lexer.LBrace:
+				depth++
+			case lexer.RBrace:
+				depth--
+			}
+			p.nextToken()
+		}
+		line = p.lastEnd().Line
+	}
+
+	for p.Pos < len(p.Tokens) {
+		if p.currentToken().Pos.Line > line {
+			return
+		}
+		switch p.currentToken().Type {
+		case lexer.OutKeyword, lexer.VarKeyword, lexer.ForDudeKeyword, lexer.IfKeyword, lexer.FunDudeKeyword,
+			lexer.ReturnKeyword, lexer.WhileDudeKeyword, lexer.BreakKeyword, lexer.ContinueKeyword, lexer.LBrace, lexer.RBrace:
+			return
+		}
+		p.nextToken()
 	}
 }
 
 func (p *Parser) parseStringLiteral() (*ASTNode, error) {
+	token := p.currentToken()
 	node := &ASTNode{
 		Type:  NodeStringLiteral,
-		Value: p.currentToken().Value,
+		Value: token.Value,
+		Start: token.Pos,
+		End:   token.End,
 	}
 	p.nextToken()
 	return node, nil
 }
 
 func (p *Parser) parseNumberLiteral() (*ASTNode, error) {
-	value, err := strconv.Atoi(p.currentToken().Value)
+	token := p.currentToken()
+	value, err := parseNumber(token.Value)
 	if err != nil {
-		return nil, fmt.Errorf("Invalid number: %s", p.currentToken().Value)
+		return nil, p.errorf(diagnostics.CodeInvalidNumber, "Invalid number: %s", token.Value)
 	}
 
 	node := &ASTNode{
 		Type:  NodeNumberLiteral,
 		Value: value,
+		Start: token.Pos,
+		End:   token.End,
 	}
 
 	p.nextToken()
 	return node, nil
 }
 
+// parseNumber converts the text of a number literal, it gives an int64 (a *big.Int when it
+// does not fit) or a float64 when the number has a fraction or an exponent. The 0x, 0o and 0b prefixes and '_' between
+// the digits are accepted.
+func parseNumber(text string) (interface{}, error) {
+	lower := strings.ToLower(text)
+	prefixed := strings.HasPrefix(lower, "0x") || strings.HasPrefix(lower, "0o") || strings.HasPrefix(lower, "0b")
+	if !prefixed && strings.ContainsAny(lower, ".e") {
+		return strconv.ParseFloat(text, 64)
+	}
+	// Without a prefix the number is decimal, also with leading zeros: 010 is ten and not
+	// an octal eight like in C. The base 0 of ParseInt is only used for the prefixes.
+	base := 0
+	if !prefixed {
+		if strings.Contains(text, "__") || strings.HasPrefix(text, "_") || strings.HasSuffix(text, "_") {
+			return nil, strconv.ErrSyntax
+		}
+		text, base = strings.ReplaceAll(text, "_", ""), 10
+	}
+	value, err := strconv.ParseInt(text, base, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		// Too large for 64 bits, the interpreter keeps it as a big int.
+		if n, ok := new(big.Int).SetString(text, base); ok {
+			return n, nil
+		}
+	}
+	return value, err
+}
+
+func (p *Parser) parseBooleanLiteral() (*ASTNode, error) {
+	token := p.currentToken()
+	node := &ASTNode{
+		Type:  NodeBooleanLiteral,
+		Value: token.Value == "true",
+		Start: token.Pos,
+		End:   token.End,
+	}
+	p.nextToken()
+	return node, nil
+}
+
 func (p *Parser) parseIdentifier() (*ASTNode, error) {
+	token := p.currentToken()
 	node := &ASTNode{
 		Type:  NodeIdentifier,
-		Value: p.currentToken().Value,
+		Value: token.Value,
+		Start: token.Pos,
+		End:   token.End,
 	}
 	p.nextToken()
 	return node, nil
 }
 
 // Main function which parse out the things.
+// Syntax errors do not stop the parsing, all of them are returned together as a diagnostics.List.
 func (p *Parser) Parse() ([]*ASTNode, error) {
 	var nodes []*ASTNode
+	p.errors = nil
 
-	parser := map[lexer.TokenType]func() (*ASTNode, error) {
-		lexer.OutKeyword: p.parsePrintStatement,
-		lexer.VarKeyword: p.parseVariableDeclaration,
-		lexer.ForDudeKeyword: p.parseForLoop,
-		lexer.IfKeyword: p.parseIfStatement,
-		lexer.LBrace: p.parseBlock,
+	for p.Pos < len(p.Tokens) {
+		stmtPos := p.Pos
+		astNode, err := p.parseStatement()
+		if err != nil {
+			p.recordError(err)
+			p.synchronize(stmtPos)
+			continue
+		}
+		nodes = append(nodes, astNode)
 	}
 
-	for p.Pos < len(p.Tokens) {
-		token := p.currentToken()
+	if len(p.errors) > 0 {
+		return nil, diagnostics.List(p.errors)
+	}
+	return nodes, nil
+}
 
-		if parser, exists := parser[token.Type]; exists {
-			astNode, err := parser()
-			if err != nil {
-				return nil, err
-			}
-			nodes = append(nodes, astNode)
-		} else {
-			p.nextToken()
-		}
+// ParseExpression parses the tokens as one single expression, the REPL uses it to
+// print the value when a line is an expression.
+func (p *Parser) ParseExpression() (*ASTNode, error) {
+	expr, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if p.Pos < len(p.Tokens) {
+		return nil, p.unexpectedToken()
 	}
-		return nodes,nil
-
-	// 	switch token.Type {
-	// 	case lexer.OutKeyword:
-	// 		astNode, err := p.parsePrintStatement()
-	// 		if err != nil {
-	// 			return nil, err
-	// 		}
-	// 		Nodes = append(Nodes, astNode)
-	// 	case lexer.VarKeyword:
-	// 		astNode, err := p.parseVariableDeclaration()
-	// 		if err != nil {
-	// 			return nil, err
-	// 		}
-	// 		Nodes = append(Nodes, astNode)
-	// 	case lexer.ForDudeKeyword:
-	// 		astNode, err := p.parseForLoop()
-	// 		if err != nil {
-	// 			return nil, err
-	// 		}
-	// 		Nodes = append(Nodes, astNode)
-	// 	case lexer.IfKeyword:
-	// 		astNode, err := p.parseIfStatement()
-	// 		if err != nil {
-	// 			return nil, err
-	// 		}
-	// 		Nodes = append(Nodes, astNode)
-	// 	case lexer.LBrace:
-	// 		astNode, err := p.parseBlock()
-	// 		if err != nil {
-	// 			return nil, err
-	// 		}
-	// 		Nodes = append(Nodes, astNode)
-	// 	default:
-	// 		p.nextToken()
-	// 	}
-	// }
-	// return Nodes, nil
-}
\ No newline at end of file
+	return expr, nil
+}
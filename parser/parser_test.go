@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AdityaByte/AdiLang/diagnostics"
+	"github.com/AdityaByte/AdiLang/lexer"
+)
+
+// parseErrors parses the source and gives its syntax errors as "line:column code message" lines.
+func parseErrors(t *testing.T, src string) []string {
+	t.Helper()
+	p := Parser{Tokens: lexer.Lexer(src)}
+	_, err := p.Parse()
+	if err == nil {
+		return nil
+	}
+	var list diagnostics.List
+	if !errors.As(err, &list) {
+		t.Fatalf("Parse returned %T, want a diagnostics.List", err)
+	}
+	var lines []string
+	for _, d := range list {
+		lines = append(lines, fmt.Sprintf("%d:%d %s %s", d.Start.Line, d.Start.Column, d.Code, d.Message))
+	}
+	return lines
+}
+
+func TestParseRecovery(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			name: "valid program",
+			src:  "var(x = 1)\nx = 5\nout->x\n",
+		},
+		{
+			name: "stray tokens at the top level",
+			src:  "var(x = 1)\n) x = 5\nout->x\n",
+			want: []string{`2:1 E0001 unexpected token: ")"`},
+		},
+		{
+			name: "misspelled keyword",
+			src:  "fordud i in range(3) { out->i }\nout->1\n",
+			want: []string{`1:1 E0001 unexpected token: "fordud"`},
+		},
+		{
+			name: "missing value after =",
+			src:  "var(x = 1)\nx =\nout->x\n",
+			want: []string{"2:4 E0002 Expected Expression at the end of the line"},
+		},
+		{
+			name: "every line with an error is reported",
+			src:  "var(x = )\nout->1\ny =\nout->2 +\nout->3\n",
+			want: []string{
+				"1:9 E0002 Expected Expression (string, number, identifier or '(')",
+				"3:4 E0002 Expected Expression at the end of the line",
+				"4:9 E0002 Expected Expression at the end of the line",
+			},
+		},
+		{
+			name: "errors inside a block",
+			src:  "fundude f() {\n  out->\n  var(y = )\n  return 1\n}\nout->f()\n",
+			want: []string{
+				"2:8 E0002 Expected Expression at the end of the line",
+				"3:11 E0002 Expected Expression (string, number, identifier or '(')",
+			},
+		},
+		{
+			name: "brace read as a map is skipped",
+			src:  "ifdude 1 > { out->1 }\nout->2\n",
+			want: []string{"1:14 E0002 Expected Expression (string, number, identifier or '(')"},
+		},
+		{
+			name: "broken map literal",
+			src:  "var(m = {\"a\": 1, \"b\" 2})\nout->m\n",
+			want: []string{"1:22 E0002 Expected ':' after the map key"},
+		},
+		{
+			name: "unclosed block",
+			src:  "fordude i in range(3) {\n  out->i\n",
+			want: []string{"1:23 E0004 expected '}' to close this block"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseErrors(t, tt.src)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got errors\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestParseStrayAssignment(t *testing.T) {
+	p := Parser{Tokens: lexer.Lexer("x = 5\n")}
+	nodes, err := p.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 || nodes[0].Type != NodeAssignment {
+		t.Fatalf("got %v, want one assignment", nodes)
+	}
+}
+
+func TestModuloHint(t *testing.T) {
+	p := Parser{Tokens: lexer.Lexer("var(x = 1)\nout->x % note %\n")}
+	_, err := p.Parse()
+	var list diagnostics.List
+	if !errors.As(err, &list) || len(list) != 1 {
+		t.Fatalf("got %v, want one error", err)
+	}
+	if !strings.Contains(list[0].Help, "//") {
+		t.Errorf("help = %q, want a hint to use //", list[0].Help)
+	}
+}
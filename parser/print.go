@@ -0,0 +1,42 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Fprint writes the tree of the nodes with one node per line, the children
+// are indented under their parent.
+func Fprint(w io.Writer, nodes []*ASTNode) {
+	for _, node := range nodes {
+		fprintNode(w, node, 0)
+	}
+}
+
+func fprintNode(w io.Writer, node *ASTNode, depth int) {
+	indent := strings.Repeat("  ", depth)
+	if node == nil {
+		fmt.Fprintf(w, "%s<nil>\n", indent)
+		return
+	}
+
+	line := fmt.Sprintf("%s%s", indent, node.Type)
+	// The print statement keeps its expression in Value, it is shown as a child.
+	child, valueIsNode := node.Value.(*ASTNode)
+	switch value := node.Value.(type) {
+	case nil, *ASTNode:
+	case string:
+		line += fmt.Sprintf(" %q", value)
+	default:
+		line += fmt.Sprintf(" %v", value)
+	}
+	fmt.Fprintf(w, "%s  %d:%d\n", line, node.Start.Line, node.Start.Column)
+
+	if valueIsNode {
+		fprintNode(w, child, depth+1)
+	}
+	for _, c := range node.Children {
+		fprintNode(w, c, depth+1)
+	}
+}
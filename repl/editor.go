@@ -0,0 +1,221 @@
+package repl
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// errInterrupted is returned by readLine when ctrl-c is pressed.
+var errInterrupted = errors.New("interrupted")
+
+// editor reads the lines of the REPL. On a terminal the line can be edited with the arrow
+// keys and the history is browsed with up and down, otherwise the lines are read as they come.
+type editor struct {
+	in      *bufio.Reader
+	out     io.Writer
+	fd      uintptr
+	history *history
+}
+
+func newEditor(in *os.File, out io.Writer, h *history) *editor {
+	return &editor{in: bufio.NewReader(in), out: out, fd: in.Fd(), history: h}
+}
+
+func (e *editor) readLine(prompt string) (string, error) {
+	restore, err := makeRaw(e.fd)
+	if err != nil {
+		return e.readPlainLine(prompt)
+	}
+	defer restore()
+	line, err := e.readRawLine(prompt)
+	if err == nil {
+		// Only the lines typed on a terminal go in the history, not piped input.
+		e.history.add(strings.TrimSpace(line))
+	}
+	return line, err
+}
+
+func (e *editor) readPlainLine(prompt string) (string, error) {
+	fmt.Fprint(e.out, prompt)
+	line, err := e.in.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// Keys sent by the terminal in raw mode.
+const (
+	keyCtrlA     = 1
+	keyCtrlB     = 2
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyCtrlF     = 6
+	keyBackspace = 8
+	keyTab       = 9
+	keyCtrlK     = 11
+	keyCtrlL     = 12
+	keyEnter     = 13
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyCtrlU     = 21
+	keyEscape    = 27
+	keyDelete    = 127
+)
+
+// lineState is the line being edited, pos is the cursor position in runes.
+type lineState struct {
+	prompt string
+	buf    []rune
+	pos    int
+}
+
+func (e *editor) readRawLine(prompt string) (string, error) {
+	line := &lineState{prompt: prompt}
+	// historyIndex is the history entry shown, len(lines) is the new line being typed which is kept in draft.
+	historyIndex := len(e.history.lines)
+	var draft []rune
+
+	showHistory := func(index int) {
+		if historyIndex == len(e.history.lines) {
+			draft = line.buf
+		}
+		historyIndex = index
+		if index == len(e.history.lines) {
+			line.buf = draft
+		} else {
+			line.buf = []rune(e.history.lines[index])
+		}
+		line.pos = len(line.buf)
+	}
+
+	e.refresh(line)
+	for {
+		char, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch char {
+		case keyEnter, '\n':
+			fmt.Fprint(e.out, "\r\n")
+			return string(line.buf), nil
+		case keyCtrlC:
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", errInterrupted
+		case keyCtrlD:
+			if len(line.buf) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			line.deleteAt(line.pos)
+		case keyBackspace, keyDelete:
+			if line.pos > 0 {
+				line.pos--
+				line.deleteAt(line.pos)
+			}
+		case keyCtrlA:
+			line.pos = 0
+		case keyCtrlE:
+			line.pos = len(line.buf)
+		case keyCtrlB:
+			line.pos = max(0, line.pos-1)
+		case keyCtrlF:
+			line.pos = min(len(line.buf), line.pos+1)
+		case keyCtrlK:
+			line.buf = line.buf[:line.pos]
+		case keyCtrlU:
+			line.buf = append([]rune{}, line.buf[line.pos:]...)
+			line.pos = 0
+		case keyCtrlL:
+			fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+		case keyCtrlP:
+			if historyIndex > 0 {
+				showHistory(historyIndex - 1)
+			}
+		case keyCtrlN:
+			if historyIndex < len(e.history.lines) {
+				showHistory(historyIndex + 1)
+			}
+		case keyTab:
+			line.insert([]rune("    "))
+		case keyEscape:
+			switch e.readEscape() {
+			case "A":
+				if historyIndex > 0 {
+					showHistory(historyIndex - 1)
+				}
+			case "B":
+				if historyIndex < len(e.history.lines) {
+					showHistory(historyIndex + 1)
+				}
+			case "C":
+				line.pos = min(len(line.buf), line.pos+1)
+			case "D":
+				line.pos = max(0, line.pos-1)
+			case "H", "1~", "7~":
+				line.pos = 0
+			case "F", "4~", "8~":
+				line.pos = len(line.buf)
+			case "3~":
+				line.deleteAt(line.pos)
+			}
+		default:
+			if char >= ' ' {
+				line.insert([]rune{char})
+			}
+		}
+		e.refresh(line)
+	}
+}
+
+// readEscape reads the rest of an escape sequence like "\x1b[A" and gives the part after
+// the "[" or "O", the unknown sequences give an empty string.
+func (e *editor) readEscape() string {
+	next, _, err := e.in.ReadRune()
+	if err != nil || (next != '[' && next != 'O') {
+		return ""
+	}
+	var seq strings.Builder
+	for {
+		char, _, err := e.in.ReadRune()
+		if err != nil {
+			return ""
+		}
+		seq.WriteRune(char)
+		// The sequence ends with a letter or a '~', the digits before it are parameters.
+		if (char >= 'A' && char <= 'Z') || (char >= 'a' && char <= 'z') || char == '~' {
+			return seq.String()
+		}
+		if seq.Len() > 8 {
+			return ""
+		}
+	}
+}
+
+// refresh draws the line again and puts the cursor at its position.
+func (e *editor) refresh(line *lineState) {
+	fmt.Fprintf(e.out, "\r%s%s\x1b[K", line.prompt, string(line.buf))
+	if back := len(line.buf) - line.pos; back > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", back)
+	}
+}
+
+func (l *lineState) insert(chars []rune) {
+	buf := make([]rune, 0, len(l.buf)+len(chars))
+	buf = append(buf, l.buf[:l.pos]...)
+	buf = append(buf, chars...)
+	l.buf = append(buf, l.buf[l.pos:]...)
+	l.pos += len(chars)
+}
+
+func (l *lineState) deleteAt(pos int) {
+	if pos < len(l.buf) {
+		l.buf = append(l.buf[:pos:pos], l.buf[pos+1:]...)
+	}
+}
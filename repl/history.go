@@ -0,0 +1,81 @@
+package repl
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+)
+
+// maxHistory is how many lines are kept in the history file.
+const maxHistory = 1000
+
+// history keeps the entered lines and saves them in a file so they are back in the next session.
+type history struct {
+	lines []string
+	path  string
+}
+
+// historyPath is ~/.adilang_history, it is empty when the home directory is not known.
+func historyPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".adilang_history")
+}
+
+// loadHistory reads the history file, a missing file is an empty history.
+func loadHistory(path string) *history {
+	h := &history{path: path}
+	if path == "" {
+		return h
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return h
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			h.lines = append(h.lines, line)
+		}
+	}
+	file.Close()
+
+	if len(h.lines) > maxHistory {
+		h.lines = h.lines[len(h.lines)-maxHistory:]
+		h.rewrite()
+	}
+	return h
+}
+
+// rewrite writes the whole file again, it is used to drop the lines over maxHistory.
+func (h *history) rewrite() {
+	file, err := os.Create(h.path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	for _, line := range h.lines {
+		file.WriteString(line + "\n")
+	}
+}
+
+// add appends the line to the history and the file, a line equal to the previous one is skipped.
+func (h *history) add(line string) {
+	if line == "" || (len(h.lines) > 0 && h.lines[len(h.lines)-1] == line) {
+		return
+	}
+	h.lines = append(h.lines, line)
+	if h.path == "" {
+		return
+	}
+	// Saving the history is best effort, the REPL works without it.
+	file, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	file.WriteString(line + "\n")
+}
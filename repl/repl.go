@@ -0,0 +1,206 @@
+// Package repl is the interactive mode of adilang, the lines are run one by one
+// in the same environment so the variables and functions stay around.
+package repl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/AdityaByte/AdiLang/diagnostics"
+	"github.com/AdityaByte/AdiLang/interpreter"
+	"github.com/AdityaByte/AdiLang/lexer"
+	"github.com/AdityaByte/AdiLang/parser"
+)
+
+const (
+	prompt             = ">> "
+	continuationPrompt = ".. "
+)
+
+const helpText = `Type a statement or an expression, the value of an expression is printed.
+Commands:
+  :env          show the variables
+  :ast code     show the syntax tree of the code
+  :tokens code  show the tokens of the code
+  :load file    run a .adi file in the current environment
+  :reset        forget all the variables
+  :help         show this help
+  :quit         leave, ctrl-d does the same
+`
+
+// REPL keeps the interpreter and the environment of the session.
+type REPL struct {
+	interp *interpreter.Interpreter
+	env    *interpreter.Environment
+	out    io.Writer
+	editor *editor
+}
+
+// New creates a REPL reading from in and writing the results and errors to out,
+// the lines typed on a terminal are saved in the history in ~/.adilang_history.
+func New(interp *interpreter.Interpreter, in *os.File, out io.Writer) *REPL {
+	editor := newEditor(in, out, loadHistory(historyPath()))
+	// input() reads from the buffer of the editor, with its own buffer it would miss the
+	// lines the editor already read ahead from piped input.
+	interp.Stdin = editor.in
+	return &REPL{
+		interp: interp,
+		env:    interpreter.NewEnvironment(nil),
+		out:    out,
+		editor: editor,
+	}
+}
+
+// Run reads and runs the input until ctrl-d, the end of the input or :quit.
+func (r *REPL) Run() error {
+	fmt.Fprintln(r.out, "AdiLang REPL, :help for the commands, ctrl-d to leave")
+
+	// input collects the lines of a statement which goes over multiple lines, like a block.
+	var input strings.Builder
+	for {
+		currentPrompt := prompt
+		if input.Len() > 0 {
+			currentPrompt = continuationPrompt
+		}
+
+		line, err := r.editor.readLine(currentPrompt)
+		if errors.Is(err, errInterrupted) {
+			input.Reset()
+			continue
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if input.Len() == 0 && strings.HasPrefix(strings.TrimSpace(line), ":") {
+			if quit := r.command(strings.TrimSpace(line)); quit {
+				return nil
+			}
+			continue
+		}
+
+		input.WriteString(line)
+		input.WriteString("\n")
+		if lexer.IsIncomplete(input.String()) {
+			continue
+		}
+
+		source := input.String()
+		input.Reset()
+		if strings.TrimSpace(source) != "" {
+			r.eval(source)
+		}
+	}
+}
+
+// eval runs the source, when it is a single expression its value is printed.
+func (r *REPL) eval(source string) {
+	tokens := lexer.Lexer(source)
+
+	p := parser.Parser{Tokens: tokens}
+	if expr, err := p.ParseExpression(); err == nil {
+		value, err := r.interp.Evaluate(expr, r.env)
+		if err != nil {
+			r.report(err, source)
+			return
+		}
+		if _, isNil := value.(interpreter.Nil); !isNil {
+			fmt.Fprintln(r.out, interpreter.Inspect(value))
+		}
+		return
+	}
+
+	p = parser.Parser{Tokens: tokens}
+	nodes, err := p.Parse()
+	if err != nil {
+		r.report(err, source)
+		return
+	}
+	if err := r.interp.Interpret(nodes, r.env); err != nil {
+		r.report(err, source)
+	}
+}
+
+// command runs a meta-command like :env, it reports if the REPL has to stop.
+func (r *REPL) command(line string) bool {
+	name, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch name {
+	case ":quit", ":q", ":exit":
+		return true
+	case ":help":
+		fmt.Fprint(r.out, helpText)
+	case ":env":
+		for _, name := range r.env.Names() {
+			value, _ := r.env.Get(name)
+			fmt.Fprintf(r.out, "%s = %s\n", name, interpreter.Inspect(value))
+		}
+	case ":reset":
+		r.env = interpreter.NewEnvironment(nil)
+		fmt.Fprintln(r.out, "environment cleared")
+	case ":tokens":
+		lexer.Fprint(r.out, lexer.Lexer(arg))
+	case ":ast":
+		p := parser.Parser{Tokens: lexer.Lexer(arg)}
+		nodes, err := p.Parse()
+		if err != nil {
+			r.report(err, arg)
+			break
+		}
+		parser.Fprint(r.out, nodes)
+	case ":load":
+		r.load(arg)
+	default:
+		fmt.Fprintf(r.out, "unknown command %s, :help shows the commands\n", name)
+	}
+	return false
+}
+
+// load runs a file in the environment of the REPL so its functions can be used afterwards.
+func (r *REPL) load(filename string) {
+	if filename == "" {
+		fmt.Fprintln(r.out, "usage: :load file.adi")
+		return
+	}
+	code, err := os.ReadFile(filename)
+	if err != nil {
+		fmt.Fprintln(r.out, "Error:", err)
+		return
+	}
+
+	source := string(code)
+	p := parser.Parser{Tokens: lexer.LexFile(filename, source)}
+	nodes, err := p.Parse()
+	if err != nil {
+		r.report(err, source)
+		return
+	}
+	if err := r.interp.Interpret(nodes, r.env); err != nil {
+		r.report(err, source)
+	}
+}
+
+func (r *REPL) report(err error, source string) {
+	var list diagnostics.List
+	var diag *diagnostics.Diagnostic
+
+	switch {
+	case errors.As(err, &list):
+	case errors.As(err, &diag):
+		list = diagnostics.List{diag}
+	default:
+		fmt.Fprintln(r.out, "Error:", err)
+		return
+	}
+	for _, d := range list {
+		diagnostics.Render(r.out, d, source)
+		fmt.Fprintln(r.out)
+	}
+}
@@ -0,0 +1,53 @@
+package repl
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AdityaByte/AdiLang/interpreter"
+)
+
+// runPiped runs the REPL on the input like "adilang < file" and gives what it printed.
+func runPiped(t *testing.T, input string) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	go func() {
+		w.WriteString(input)
+		w.Close()
+	}()
+
+	var out strings.Builder
+	interp := interpreter.NewInterpreter()
+	interp.Stdout = &out
+	if err := New(interp, r, &out).Run(); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestInputReadsThePipedLines(t *testing.T) {
+	out := runPiped(t, "var(n = input())\nhello\nout->n\nout->input(\"name? \")\nworld\n")
+	for _, want := range []string{"hello\n", "name? world\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output %q does not contain %q", out, want)
+		}
+	}
+	if strings.Contains(out, "undefined variable") {
+		t.Errorf("the line for input() was run as code: %q", out)
+	}
+}
+
+func TestPipedInputIsNotInTheHistory(t *testing.T) {
+	runPiped(t, "out->1\n")
+	home, _ := os.UserHomeDir()
+	if _, err := os.Stat(filepath.Join(home, ".adilang_history")); !os.IsNotExist(err) {
+		t.Errorf("the history file was written for piped input: %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package repl
+
+import "errors"
+
+// makeRaw is only implemented for linux, the other systems read plain lines without editing.
+func makeRaw(fd uintptr) (func(), error) {
+	return nil, errors.New("raw terminal mode is not supported on this system")
+}